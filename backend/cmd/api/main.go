@@ -2,19 +2,31 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"path"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
-	"time"
 
+	"meeting-planner/backend"
+	"meeting-planner/backend/internal/config"
 	"meeting-planner/backend/internal/db"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/frontend"
 	"meeting-planner/backend/internal/handlers"
+	"meeting-planner/backend/internal/logging"
+	"meeting-planner/backend/internal/metrics"
 	"meeting-planner/backend/internal/middleware"
+	"meeting-planner/backend/internal/notifications"
+	"meeting-planner/backend/internal/ratelimit"
+	"meeting-planner/backend/internal/tracing"
+	"meeting-planner/backend/internal/webhooks"
 
 	"github.com/joho/godotenv"
 )
@@ -22,50 +34,108 @@ import (
 func main() {
 	_ = godotenv.Load()
 
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	flag.Parse()
+
+	slog.SetDefault(logging.New(os.Stdout, slog.LevelInfo))
+
+	appConfig, configError := config.Load(*configPath)
+	if configError != nil {
+		fatal("failed to load configuration", "error", configError)
+	}
+
+	slog.SetDefault(logging.New(os.Stdout, appConfig.Logging.Level))
+	slog.Info("loaded configuration", "file", *configPath, "config", appConfig)
+
 	backgroundContext := context.Background()
 
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		log.Fatal("DATABASE_URL environment variable is required")
+	shutdownTracing, tracingError := tracing.Setup(backgroundContext, appConfig.Tracing.Exporter)
+	if tracingError != nil {
+		fatal("failed to set up tracing", "error", tracingError)
 	}
 
-	database, initializationError := db.Init(backgroundContext, databaseURL)
+	database, initializationError := db.Init(backgroundContext, appConfig.Database)
 	if initializationError != nil {
-		log.Fatalf("Failed to initialize database: %v", initializationError)
+		fatal("failed to initialize database", "error", initializationError)
 	}
 	defer database.Close()
 
-	handlerInstance := handlers.New(database)
-	routeMux := setupRoutes(handlerInstance)
+	metrics.RegisterPool(database.Pool)
+
+	accessTokenSecret := []byte(appConfig.Security.AccessTokenSecret)
+	if len(accessTokenSecret) == 0 {
+		slog.Warn("ACCESS_TOKEN_SECRET is not set, using a random secret; calendar access tokens will not survive restarts")
+		accessTokenSecret = make([]byte, 32)
+		_, _ = rand.Read(accessTokenSecret)
+	}
+
+	notificationSender, senderError := newNotificationSender(appConfig.Email)
+	if senderError != nil {
+		fatal("failed to configure email notifications", "error", senderError)
+	}
+	notifier := notifications.NewNotifier(notificationSender, appConfig.Server.PublicURL)
+
+	eventHub := events.NewHub()
+
+	webhookOptions := webhooks.DefaultOptions
+	webhookOptions.Workers = appConfig.Webhooks.Workers
+	webhookOptions.MaxDeliveries = appConfig.Webhooks.MaxDeliveries
+	webhookOptions.MaxAttempts = appConfig.Webhooks.MaxAttempts
+	webhookOptions.Timeout = appConfig.Webhooks.Timeout
+	webhookOptions.DeliveryRetention = appConfig.Webhooks.DeliveryRetention
+	webhookOptions.AllowedNetworks = appConfig.Webhooks.AllowedNetworks
+
+	webhookDispatcher, dispatcherError := webhooks.NewDispatcher(database.Queries, webhookOptions)
+	if dispatcherError != nil {
+		fatal("failed to configure webhooks", "error", dispatcherError)
+	}
+	eventHub.AddListener(webhookDispatcher.Enqueue)
+	webhookDispatcher.Start()
+
+	handlerInstance := handlers.New(database, accessTokenSecret, eventHub, notifier)
+
+	backgroundWorkContext, stopBackgroundWork := context.WithCancel(backgroundContext)
+	go handlerInstance.CalendarService.WatchDeadlines(backgroundWorkContext, appConfig.Deadlines.CheckInterval)
 
-	wrappedHandler := middleware.Recovery(middleware.Logging(middleware.CORS(routeMux)))
+	rateLimiter, rateLimiterError := newRateLimiter(database, appConfig.RateLimits)
+	if rateLimiterError != nil {
+		fatal("failed to configure rate limiting", "error", rateLimiterError)
+	}
+
+	corsPolicy, corsError := newCORS(appConfig.CORS)
+	if corsError != nil {
+		fatal("failed to configure CORS", "error", corsError)
+	}
 
-	serverAddress := ":8080"
-	portEnvVar := os.Getenv("PORT")
-	if portEnvVar != "" {
-		serverAddress = ":" + portEnvVar
+	frontendServer, frontendError := newFrontendServer(appConfig.Static)
+	if frontendError != nil {
+		fatal("failed to load frontend", "error", frontendError)
 	}
 
+	routeMux := setupRoutes(handlerInstance, rateLimiter, frontendServer, appConfig)
+
+	wrappedHandler := middleware.RequestID(middleware.Recovery(middleware.Logging(middleware.Metrics(corsPolicy.Handler(middleware.Tracing(routeMux))))))
+
 	httpServer := &http.Server{
-		Addr:              serverAddress,
+		Addr:              ":" + strconv.Itoa(appConfig.Server.Port),
 		Handler:           wrappedHandler,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       10 * time.Second,
-		WriteTimeout:      10 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		ReadHeaderTimeout: appConfig.Server.ReadHeaderTimeout,
+		ReadTimeout:       appConfig.Server.ReadTimeout,
+		WriteTimeout:      appConfig.Server.WriteTimeout,
+		IdleTimeout:       appConfig.Server.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
 	}
+	httpServer.RegisterOnShutdown(eventHub.Close)
 
 	go func() {
-		log.Printf("%s", handlers.ToJSONPretty(map[string]any{
-			"message": "Server is starting",
-			"addr":    httpServer.Addr,
-			"time":    time.Now().Format(time.RFC3339),
-			"mode":    "development",
-			"sdk":     runtime.Version(),
-		}))
+		slog.Info("server is starting",
+			"addr", httpServer.Addr,
+			"mode", "development",
+			"sdk", runtime.Version(),
+		)
 
 		if serverError := httpServer.ListenAndServe(); serverError != nil && serverError != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", serverError)
+			fatal("server error", "error", serverError)
 		}
 	}()
 
@@ -73,36 +143,133 @@ func main() {
 	signal.Notify(quitSignal, syscall.SIGINT, syscall.SIGTERM)
 	<-quitSignal
 
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server")
 
-	shutdownContext, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownContext, cancelShutdown := context.WithTimeout(context.Background(), appConfig.Server.ShutdownTimeout)
 	defer cancelShutdown()
 
 	if shutdownError := httpServer.Shutdown(shutdownContext); shutdownError != nil {
-		log.Fatalf("Server forced to shutdown: %v", shutdownError)
+		fatal("server forced to shutdown", "error", shutdownError)
+	}
+
+	stopBackgroundWork()
+	webhookDispatcher.Close()
+	notifier.Close()
+
+	if tracingShutdownError := shutdownTracing(shutdownContext); tracingShutdownError != nil {
+		slog.Error("failed to flush traces", "error", tracingShutdownError)
 	}
 
-	log.Println("Server exited")
+	slog.Info("server exited")
 }
 
-func setupRoutes(handlerInstance *handlers.Handler) *http.ServeMux {
+func fatal(message string, args ...any) {
+	slog.Error(message, args...)
+	os.Exit(1)
+}
+
+// newNotificationSender sends email through the configured SMTP host and
+// drops messages when there is none.
+func newNotificationSender(emailConfig config.EmailConfig) (notifications.Sender, error) {
+	if emailConfig.SMTPHost == "" {
+		slog.Warn("email delivery is disabled because SMTP_HOST is not set")
+		return notifications.LogSender{}, nil
+	}
+
+	return notifications.NewSMTPSender(notifications.SMTPConfig{
+		Host:     emailConfig.SMTPHost,
+		Port:     emailConfig.SMTPPort,
+		Username: emailConfig.SMTPUsername,
+		Password: emailConfig.SMTPPassword,
+		From:     emailConfig.SMTPFrom,
+		Security: notifications.SMTPSecurity(emailConfig.SMTPSecurity),
+	})
+}
+
+// newRateLimiter keeps buckets in memory unless the postgres store is
+// configured, which shares them between replicas.
+func newRateLimiter(database *db.DB, limits config.RateLimitConfig) (*middleware.RateLimiter, error) {
+	clientIPs, resolverError := middleware.NewClientIPResolver(limits.TrustedProxies)
+	if resolverError != nil {
+		return nil, resolverError
+	}
+
+	var store ratelimit.Store
+	switch limits.Store {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "postgres":
+		longestPeriod := max(limits.CreateCalendar.Period, limits.Responses.Period, limits.AccessTokens.Period)
+		store = ratelimit.NewPostgresStore(database.Queries, longestPeriod)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q, expected memory or postgres", limits.Store)
+	}
+
+	return middleware.NewRateLimiter(store, clientIPs, limits.OnStoreError == "allow"), nil
+}
+
+// newCORS builds the default policy from the configured origins and layers
+// the route overrides on top; overrides keep the default methods and headers.
+func newCORS(corsConfig config.CORSConfig) (*middleware.CORS, error) {
+	defaultPolicy := middleware.DefaultCORSPolicy
+	defaultPolicy.AllowedOrigins = corsConfig.AllowedOrigins
+	defaultPolicy.AllowCredentials = corsConfig.AllowCredentials
+	defaultPolicy.MaxAge = corsConfig.MaxAge
+
+	corsPolicy, policyError := middleware.NewCORS(defaultPolicy)
+	if policyError != nil {
+		return nil, policyError
+	}
+
+	for _, route := range corsConfig.Routes {
+		routePolicy := defaultPolicy
+		routePolicy.AllowedOrigins = route.AllowedOrigins
+		routePolicy.AllowCredentials = route.AllowCredentials
+
+		if routeError := corsPolicy.Route(route.Pattern, routePolicy); routeError != nil {
+			return nil, routeError
+		}
+	}
+
+	return corsPolicy, nil
+}
+
+func setupRoutes(handlerInstance *handlers.Handler, rateLimiter *middleware.RateLimiter, frontendServer *frontend.Server, appConfig config.Config) *http.ServeMux {
 	routeMux := http.NewServeMux()
+	limits := appConfig.RateLimits
 
 	routeMux.HandleFunc("GET /api/health", handlerInstance.HealthcheckEndpoint)
+	routeMux.Handle("GET /metrics", metrics.Handler())
 	routeMux.HandleFunc("POST /api/echo/{id}", handlerInstance.EchoEndpoint)
 
-	routeMux.HandleFunc("POST /api/calendars", handlerInstance.CreateCalendarEndpoint)
+	routeMux.Handle("POST /api/calendars", rateLimiter.Limit(limits.CreateCalendar, http.HandlerFunc(handlerInstance.CreateCalendarEndpoint)))
+	routeMux.HandleFunc("GET /api/calendars/{calendar_id}", handlerInstance.GetCalendarEndpoint)
+	routeMux.HandleFunc("PATCH /api/calendars/{calendar_id}", handlerInstance.UpdateCalendarEndpoint)
+	routeMux.HandleFunc("DELETE /api/calendars/{calendar_id}", handlerInstance.DeleteCalendarEndpoint)
+	routeMux.HandleFunc("PUT /api/calendars/{calendar_id}/status", handlerInstance.UpdateCalendarStatusEndpoint)
+	routeMux.HandleFunc("GET /api/calendars/{calendar_id}/results", handlerInstance.GetCalendarResultsEndpoint)
+	routeMux.HandleFunc("GET /api/calendars/{calendar_id}/events", handlerInstance.CalendarEventsEndpoint)
+	routeMux.HandleFunc("GET /api/calendars/{calendar_id}/calendar.ics", handlerInstance.ExportCalendarICSEndpoint)
 	routeMux.HandleFunc("POST /api/calendars/{calendar_id}/time-slots", handlerInstance.CreateCalendarTimeSlotsEndpoint)
-	// routeMux.HandleFunc("GET /api/calendars/{id}", handlerInstance.GetCalendar)
+	routeMux.HandleFunc("POST /api/calendars/{calendar_id}/time-slots/generate", handlerInstance.GenerateCalendarTimeSlotsEndpoint)
+	routeMux.HandleFunc("PUT /api/calendars/{calendar_id}/time-slots/{time_slot_id}", handlerInstance.UpdateCalendarTimeSlotEndpoint)
+	routeMux.HandleFunc("DELETE /api/calendars/{calendar_id}/time-slots/{time_slot_id}", handlerInstance.DeleteCalendarTimeSlotEndpoint)
+	routeMux.HandleFunc("POST /api/calendars/{calendar_id}/webhooks", handlerInstance.CreateWebhookEndpoint)
+	routeMux.HandleFunc("GET /api/calendars/{calendar_id}/webhooks", handlerInstance.ListWebhooksEndpoint)
+	routeMux.HandleFunc("DELETE /api/calendars/{calendar_id}/webhooks/{webhook_id}", handlerInstance.DeleteWebhookEndpoint)
+	routeMux.HandleFunc("GET /api/calendars/{calendar_id}/webhooks/{webhook_id}/deliveries", handlerInstance.ListWebhookDeliveriesEndpoint)
+	routeMux.Handle("POST /api/calendars/{calendar_id}/access-tokens", rateLimiter.Limit(limits.AccessTokens, http.HandlerFunc(handlerInstance.CreateAccessTokenEndpoint)))
+	routeMux.Handle("POST /api/calendars/{calendar_id}/votes", rateLimiter.Limit(limits.Responses, http.HandlerFunc(handlerInstance.CreateVotesEndpoint)))
+	routeMux.Handle("PUT /api/calendars/{calendar_id}/participants/{participant_id}", rateLimiter.Limit(limits.Responses, http.HandlerFunc(handlerInstance.UpdateParticipantEndpoint)))
+	routeMux.Handle("DELETE /api/calendars/{calendar_id}/participants/{participant_id}", rateLimiter.Limit(limits.Responses, http.HandlerFunc(handlerInstance.DeleteParticipantEndpoint)))
+	routeMux.Handle("POST /api/calendars/{calendar_id}/availability/import", rateLimiter.Limit(limits.Responses, http.HandlerFunc(handlerInstance.ImportAvailabilityEndpoint)))
 
-	setupStaticFileServer(routeMux)
+	setupStaticFileServer(routeMux, frontendServer)
 
 	return routeMux
 }
 
-func setupStaticFileServer(routeMux *http.ServeMux) {
-	staticDirectory := "public"
-
+func setupStaticFileServer(routeMux *http.ServeMux, frontendServer *frontend.Server) {
 	routeMux.Handle("/", http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		if request.Method != http.MethodGet && request.Method != http.MethodHead {
 			http.Error(writer, "Method Not Allowed", http.StatusMethodNotAllowed)
@@ -114,29 +281,17 @@ func setupStaticFileServer(routeMux *http.ServeMux) {
 			return
 		}
 
-		requestedPath := path.Clean(request.URL.Path)
-		if requestedPath == "/" {
-			requestedPath = "/index.html"
-		}
-
-		requestedPath = strings.TrimPrefix(requestedPath, "/")
-		if strings.Contains(requestedPath, "..") {
-			http.NotFound(writer, request)
-			return
-		}
-
-		filePath := path.Join(staticDirectory, requestedPath)
-		if _, fileStatError := os.Stat(filePath); fileStatError == nil {
-			http.ServeFile(writer, request, filePath)
-			return
-		}
+		frontendServer.ServeHTTP(writer, request)
+	}))
+}
 
-		indexPath := path.Join(staticDirectory, "index.html")
-		if _, indexStatError := os.Stat(indexPath); indexStatError == nil {
-			http.ServeFile(writer, request, indexPath)
-			return
-		}
+// newFrontendServer serves the client embedded in the binary unless a
+// directory on disk is configured for development.
+func newFrontendServer(staticConfig config.StaticConfig) (*frontend.Server, error) {
+	if staticConfig.Directory != "" {
+		slog.Info("serving frontend from disk", "directory", staticConfig.Directory)
+		return frontend.NewLive(os.DirFS(staticConfig.Directory)), nil
+	}
 
-		http.NotFound(writer, request)
-	}))
+	return frontend.New(backend.Frontend())
 }
@@ -0,0 +1,21 @@
+// Package backend embeds the built client so the API server ships as a
+// single binary. Run yarn build:copy in the client directory to refresh it.
+package backend
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed public
+var publicFiles embed.FS
+
+// Frontend returns the embedded client with public/ stripped, so index.html
+// sits at the root.
+func Frontend() fs.FS {
+	frontendFiles, subError := fs.Sub(publicFiles, "public")
+	if subError != nil {
+		panic(subError)
+	}
+	return frontendFiles
+}
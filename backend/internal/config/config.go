@@ -0,0 +1,310 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"meeting-planner/backend/internal/ratelimit"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the API server. Values come from Default,
+// then from the optional config file, then from the environment variables
+// named in the env tags. Fields tagged secret are redacted when logged.
+type Config struct {
+	Server     ServerConfig    `yaml:"server" toml:"server"`
+	Database   DatabaseConfig  `yaml:"database" toml:"database"`
+	Security   SecurityConfig  `yaml:"security" toml:"security"`
+	CORS       CORSConfig      `yaml:"cors" toml:"cors"`
+	Static     StaticConfig    `yaml:"static" toml:"static"`
+	Logging    LoggingConfig   `yaml:"logging" toml:"logging"`
+	Tracing    TracingConfig   `yaml:"tracing" toml:"tracing"`
+	Email      EmailConfig     `yaml:"email" toml:"email"`
+	RateLimits RateLimitConfig `yaml:"rate_limits" toml:"rate_limits"`
+	Webhooks   WebhooksConfig  `yaml:"webhooks" toml:"webhooks"`
+	Deadlines  DeadlinesConfig `yaml:"deadlines" toml:"deadlines"`
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port" toml:"port" env:"PORT"`
+	// PublicURL is the address users reach the frontend at, used for links
+	// in emails. It defaults to http://localhost:<port>.
+	PublicURL         string        `yaml:"public_url" toml:"public_url" env:"PUBLIC_URL"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+}
+
+type DatabaseConfig struct {
+	URL               string        `yaml:"url" toml:"url" env:"DATABASE_URL" secret:"true"`
+	MaxConns          int32         `yaml:"max_conns" toml:"max_conns" env:"DATABASE_MAX_CONNS"`
+	MinConns          int32         `yaml:"min_conns" toml:"min_conns" env:"DATABASE_MIN_CONNS"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"DATABASE_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"DATABASE_MAX_CONN_IDLE_TIME"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period" env:"DATABASE_HEALTH_CHECK_PERIOD"`
+}
+
+type SecurityConfig struct {
+	// AccessTokenSecret signs calendar access tokens. When empty a random
+	// secret is used and tokens do not survive restarts.
+	AccessTokenSecret string `yaml:"access_token_secret" toml:"access_token_secret" env:"ACCESS_TOKEN_SECRET" secret:"true"`
+}
+
+// CORSConfig lists the browser origins allowed to call the API. Origins are
+// exact ("https://portal.example.com"), wildcard subdomains
+// ("https://*.example.com") or "*" for any origin, which cannot be combined
+// with AllowCredentials.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE"`
+	// Routes override the origins and credentials mode for the paths they
+	// match and can only be set in the config file.
+	Routes []CORSRouteConfig `yaml:"routes" toml:"routes"`
+}
+
+type CORSRouteConfig struct {
+	// Pattern is a ServeMux path pattern without a method, for example
+	// "/api/calendars/{calendar_id}/events".
+	Pattern          string   `yaml:"pattern" toml:"pattern" json:"pattern"`
+	AllowedOrigins   []string `yaml:"allowed_origins" toml:"allowed_origins" json:"allowed_origins"`
+	AllowCredentials bool     `yaml:"allow_credentials" toml:"allow_credentials" json:"allow_credentials"`
+}
+
+type StaticConfig struct {
+	// Directory serves the client from disk instead of the copy embedded in
+	// the binary, re-reading files on every request. Meant for development.
+	Directory string `yaml:"directory" toml:"directory" env:"STATIC_DIR"`
+}
+
+type LoggingConfig struct {
+	Level slog.Level `yaml:"level" toml:"level" env:"LOG_LEVEL"`
+}
+
+type TracingConfig struct {
+	// Exporter is otlp, stdout or none.
+	Exporter string `yaml:"exporter" toml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+type EmailConfig struct {
+	// SMTPHost enables sending email. When empty emails are dropped.
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" toml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+	SMTPFrom     string `yaml:"smtp_from" toml:"smtp_from" env:"SMTP_FROM"`
+	// SMTPSecurity is starttls, tls or none.
+	SMTPSecurity string `yaml:"smtp_security" toml:"smtp_security" env:"SMTP_SECURITY"`
+}
+
+type RateLimitConfig struct {
+	// Store is memory or postgres. Postgres shares limits between replicas.
+	Store string `yaml:"store" toml:"store" env:"RATE_LIMIT_STORE"`
+	// OnStoreError is allow or deny: whether requests go through while the
+	// store is failing, or are answered with 503 until it recovers.
+	OnStoreError   string          `yaml:"on_store_error" toml:"on_store_error" env:"RATE_LIMIT_ON_STORE_ERROR"`
+	TrustedProxies []string        `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	CreateCalendar ratelimit.Limit `yaml:"create_calendar" toml:"create_calendar" env:"RATE_LIMIT_CREATE_CALENDAR"`
+	Responses      ratelimit.Limit `yaml:"responses" toml:"responses" env:"RATE_LIMIT_RESPONSES"`
+	AccessTokens   ratelimit.Limit `yaml:"access_tokens" toml:"access_tokens" env:"RATE_LIMIT_ACCESS_TOKENS"`
+}
+
+type WebhooksConfig struct {
+	Workers int `yaml:"workers" toml:"workers" env:"WEBHOOK_WORKERS"`
+	// MaxDeliveries bounds the deliveries in flight at once, including the
+	// ones waiting for a retry.
+	MaxDeliveries int           `yaml:"max_deliveries" toml:"max_deliveries" env:"WEBHOOK_MAX_DELIVERIES"`
+	MaxAttempts   int           `yaml:"max_attempts" toml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	Timeout       time.Duration `yaml:"timeout" toml:"timeout" env:"WEBHOOK_TIMEOUT"`
+	// DeliveryRetention is how long the delivery log keeps each attempt.
+	DeliveryRetention time.Duration `yaml:"delivery_retention" toml:"delivery_retention" env:"WEBHOOK_DELIVERY_RETENTION"`
+	// AllowedNetworks opts internal receivers in; webhooks cannot reach
+	// loopback, private or link-local addresses otherwise.
+	AllowedNetworks []string `yaml:"allowed_networks" toml:"allowed_networks" env:"WEBHOOK_ALLOWED_NETWORKS"`
+}
+
+type DeadlinesConfig struct {
+	CheckInterval time.Duration `yaml:"check_interval" toml:"check_interval" env:"DEADLINE_CHECK_INTERVAL"`
+}
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      10 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxConns:          5,
+			MinConns:          5,
+			MaxConnLifetime:   30 * time.Minute,
+			MaxConnIdleTime:   5 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			MaxAge:         24 * time.Hour,
+		},
+		Logging: LoggingConfig{
+			Level: slog.LevelInfo,
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+		Email: EmailConfig{
+			SMTPPort:     587,
+			SMTPSecurity: "starttls",
+		},
+		RateLimits: RateLimitConfig{
+			Store:          "memory",
+			OnStoreError:   "allow",
+			CreateCalendar: ratelimit.Limit{Requests: 20, Period: time.Hour},
+			Responses:      ratelimit.Limit{Requests: 30, Period: time.Minute},
+			AccessTokens:   ratelimit.Limit{Requests: 10, Period: time.Minute},
+		},
+		Webhooks: WebhooksConfig{
+			Workers:           4,
+			MaxDeliveries:     64,
+			MaxAttempts:       6,
+			Timeout:           10 * time.Second,
+			DeliveryRetention: 30 * 24 * time.Hour,
+		},
+		Deadlines: DeadlinesConfig{
+			CheckInterval: 30 * time.Second,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the file at path (YAML
+// or TOML, picked by extension; skipped when path is empty) and the
+// environment, then validates it.
+func Load(path string) (Config, error) {
+	loadedConfig := Default()
+
+	if path != "" {
+		if fileError := loadFile(path, &loadedConfig); fileError != nil {
+			return Config{}, fileError
+		}
+	}
+
+	if envError := applyEnv(&loadedConfig); envError != nil {
+		return Config{}, envError
+	}
+
+	if loadedConfig.Server.PublicURL == "" {
+		loadedConfig.Server.PublicURL = fmt.Sprintf("http://localhost:%d", loadedConfig.Server.Port)
+	}
+
+	if validationError := loadedConfig.Validate(); validationError != nil {
+		return Config{}, fmt.Errorf("invalid configuration: %w", validationError)
+	}
+
+	return loadedConfig, nil
+}
+
+func loadFile(path string, target *Config) error {
+	fileContents, readError := os.ReadFile(path)
+	if readError != nil {
+		return fmt.Errorf("failed to read config file: %w", readError)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(strings.NewReader(string(fileContents)))
+		decoder.KnownFields(true)
+		if decodingError := decoder.Decode(target); decodingError != nil && !errors.Is(decodingError, io.EOF) {
+			return fmt.Errorf("failed to parse config file %s: %w", path, decodingError)
+		}
+	case ".toml":
+		metadata, decodingError := toml.Decode(string(fileContents), target)
+		if decodingError != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, decodingError)
+		}
+		if undecodedKeys := metadata.Undecoded(); len(undecodedKeys) > 0 {
+			return fmt.Errorf("unknown keys in config file %s: %v", path, undecodedKeys)
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s, expected .yaml, .yml or .toml", path)
+	}
+
+	return nil
+}
+
+func (c Config) Validate() error {
+	var problems []error
+	check := func(isValid bool, format string, args ...any) {
+		if !isValid {
+			problems = append(problems, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port must be between 1 and 65535")
+	if publicURL, urlError := url.Parse(c.Server.PublicURL); urlError != nil || (publicURL.Scheme != "http" && publicURL.Scheme != "https") || publicURL.Host == "" {
+		problems = append(problems, errors.New("server.public_url must be an absolute http or https URL"))
+	}
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout must be positive")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+
+	check(c.Database.URL != "", "database.url is required")
+	check(c.Database.MaxConns > 0, "database.max_conns must be positive")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns, "database.min_conns must be between 0 and database.max_conns")
+	check(c.Database.MaxConnLifetime > 0, "database.max_conn_lifetime must be positive")
+	check(c.Database.MaxConnIdleTime > 0, "database.max_conn_idle_time must be positive")
+	check(c.Database.HealthCheckPeriod > 0, "database.health_check_period must be positive")
+
+	check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins must not be empty")
+	check(c.CORS.MaxAge >= 0, "cors.max_age must not be negative")
+	for routeIndex, route := range c.CORS.Routes {
+		check(route.Pattern != "", "cors.routes[%d].pattern is required", routeIndex)
+		check(len(route.AllowedOrigins) > 0, "cors.routes[%d].allowed_origins must not be empty", routeIndex)
+	}
+
+	check(oneOf(c.Tracing.Exporter, "none", "otlp", "stdout"), "tracing.exporter must be none, otlp or stdout")
+
+	if c.Email.SMTPHost != "" {
+		check(c.Email.SMTPPort > 0 && c.Email.SMTPPort <= 65535, "email.smtp_port must be between 1 and 65535")
+		check(c.Email.SMTPFrom != "", "email.smtp_from is required when email.smtp_host is set")
+		check(oneOf(c.Email.SMTPSecurity, "starttls", "tls", "none"), "email.smtp_security must be starttls, tls or none")
+	}
+
+	check(oneOf(c.RateLimits.Store, "memory", "postgres"), "rate_limits.store must be memory or postgres")
+	check(oneOf(c.RateLimits.OnStoreError, "allow", "deny"), "rate_limits.on_store_error must be allow or deny")
+	for limitName, limit := range map[string]ratelimit.Limit{
+		"create_calendar": c.RateLimits.CreateCalendar,
+		"responses":       c.RateLimits.Responses,
+		"access_tokens":   c.RateLimits.AccessTokens,
+	} {
+		check(limit.Requests > 0 && limit.Period > 0, "rate_limits.%s must allow at least one request per positive period", limitName)
+	}
+
+	check(c.Webhooks.Workers > 0, "webhooks.workers must be positive")
+	check(c.Webhooks.MaxDeliveries > 0, "webhooks.max_deliveries must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive")
+	check(c.Webhooks.DeliveryRetention > 0, "webhooks.delivery_retention must be positive")
+
+	check(c.Deadlines.CheckInterval > 0, "deadlines.check_interval must be positive")
+
+	return errors.Join(problems...)
+}
+
+func oneOf(value string, allowedValues ...string) bool {
+	return slices.Contains(allowedValues, value)
+}
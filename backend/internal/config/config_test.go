@@ -0,0 +1,300 @@
+package config
+
+import (
+	"log/slog"
+	"meeting-planner/backend/internal/ratelimit"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv empties every variable named in an env tag, since applyEnv skips
+// empty variables, so that the environment of the test run does not leak in.
+func clearEnv(t *testing.T, structType reflect.Type) {
+	t.Helper()
+
+	for fieldIndex := 0; fieldIndex < structType.NumField(); fieldIndex++ {
+		fieldType := structType.Field(fieldIndex)
+		if envVarName := fieldType.Tag.Get("env"); envVarName != "" {
+			t.Setenv(envVarName, "")
+		} else if fieldType.Type.Kind() == reflect.Struct {
+			clearEnv(t, fieldType.Type)
+		}
+	}
+}
+
+func validConfig() Config {
+	validatedConfig := Default()
+	validatedConfig.Server.PublicURL = "http://localhost:8080"
+	validatedConfig.Database.URL = "postgres://localhost/meeting_planner"
+	return validatedConfig
+}
+
+func TestApplyEnv(t *testing.T) {
+	clearEnv(t, reflect.TypeFor[Config]())
+	t.Setenv("PORT", "9090")
+	t.Setenv("DATABASE_MAX_CONNS", "12")
+	t.Setenv("SERVER_READ_TIMEOUT", "1m30s")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com, ,https://b.example.com ")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("RATE_LIMIT_RESPONSES", "5/10s")
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+
+	loadedConfig := Default()
+	if envError := applyEnv(&loadedConfig); envError != nil {
+		t.Fatalf("applyEnv: %v", envError)
+	}
+
+	expectedConfig := Default()
+	expectedConfig.Server.Port = 9090
+	expectedConfig.Database.MaxConns = 12
+	expectedConfig.Server.ReadTimeout = 90 * time.Second
+	expectedConfig.CORS.AllowCredentials = true
+	expectedConfig.CORS.AllowedOrigins = []string{"https://a.example.com", "https://b.example.com"}
+	expectedConfig.Logging.Level = slog.LevelDebug
+	expectedConfig.RateLimits.Responses = ratelimit.Limit{Requests: 5, Period: 10 * time.Second}
+	expectedConfig.Email.SMTPHost = "smtp.example.com"
+
+	if !reflect.DeepEqual(loadedConfig, expectedConfig) {
+		t.Errorf("applyEnv = %+v, want %+v", loadedConfig, expectedConfig)
+	}
+}
+
+func TestApplyEnvRejectsInvalidValues(t *testing.T) {
+	testCases := []struct {
+		envVarName  string
+		envVarValue string
+	}{
+		{envVarName: "PORT", envVarValue: "eighty"},
+		{envVarName: "DATABASE_MAX_CONNS", envVarValue: "3000000000"},
+		{envVarName: "SERVER_READ_TIMEOUT", envVarValue: "10"},
+		{envVarName: "CORS_ALLOW_CREDENTIALS", envVarValue: "sometimes"},
+		{envVarName: "LOG_LEVEL", envVarValue: "loud"},
+		{envVarName: "RATE_LIMIT_RESPONSES", envVarValue: "5 per minute"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.envVarName, func(t *testing.T) {
+			clearEnv(t, reflect.TypeFor[Config]())
+			t.Setenv(testCase.envVarName, testCase.envVarValue)
+
+			loadedConfig := Default()
+			envError := applyEnv(&loadedConfig)
+			if envError == nil || !strings.Contains(envError.Error(), testCase.envVarName) {
+				t.Errorf("applyEnv = %v, want an error naming %s", envError, testCase.envVarName)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name         string
+		fileName     string
+		fileContents string
+	}{
+		{
+			name:     "YAML",
+			fileName: "config.yaml",
+			fileContents: "server:\n" +
+				"  port: 9000\n" +
+				"  read_timeout: 20s\n" +
+				"rate_limits:\n" +
+				"  responses: 7/1m\n" +
+				"cors:\n" +
+				"  allowed_origins: [https://app.example.com]\n",
+		},
+		{
+			name:     "TOML",
+			fileName: "config.toml",
+			fileContents: "[server]\n" +
+				"port = 9000\n" +
+				"read_timeout = \"20s\"\n" +
+				"[rate_limits]\n" +
+				"responses = \"7/1m\"\n" +
+				"[cors]\n" +
+				"allowed_origins = [\"https://app.example.com\"]\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clearEnv(t, reflect.TypeFor[Config]())
+			t.Setenv("SERVER_READ_TIMEOUT", "25s")
+			t.Setenv("DATABASE_URL", "postgres://localhost/meeting_planner")
+
+			configPath := filepath.Join(t.TempDir(), testCase.fileName)
+			if writeError := os.WriteFile(configPath, []byte(testCase.fileContents), 0o600); writeError != nil {
+				t.Fatalf("failed to write config file: %v", writeError)
+			}
+
+			loadedConfig, loadError := Load(configPath)
+			if loadError != nil {
+				t.Fatalf("Load: %v", loadError)
+			}
+
+			if loadedConfig.Server.Port != 9000 {
+				t.Errorf("server.port = %d, want 9000", loadedConfig.Server.Port)
+			}
+			if loadedConfig.Server.ReadTimeout != 25*time.Second {
+				t.Errorf("server.read_timeout = %v, want the environment to win with 25s", loadedConfig.Server.ReadTimeout)
+			}
+			if loadedConfig.Server.WriteTimeout != Default().Server.WriteTimeout {
+				t.Errorf("server.write_timeout = %v, want the default %v", loadedConfig.Server.WriteTimeout, Default().Server.WriteTimeout)
+			}
+			if loadedConfig.Server.PublicURL != "http://localhost:9000" {
+				t.Errorf("server.public_url = %q, want http://localhost:9000", loadedConfig.Server.PublicURL)
+			}
+			if loadedConfig.RateLimits.Responses != (ratelimit.Limit{Requests: 7, Period: time.Minute}) {
+				t.Errorf("rate_limits.responses = %v, want 7/1m", loadedConfig.RateLimits.Responses)
+			}
+			if !slices.Equal(loadedConfig.CORS.AllowedOrigins, []string{"https://app.example.com"}) {
+				t.Errorf("cors.allowed_origins = %v, want [https://app.example.com]", loadedConfig.CORS.AllowedOrigins)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	testCases := []struct {
+		name         string
+		fileName     string
+		fileContents string
+	}{
+		{name: "unknown YAML key", fileName: "config.yaml", fileContents: "server:\n  prot: 9000\n"},
+		{name: "unknown TOML key", fileName: "config.toml", fileContents: "[server]\nprot = 9000\n"},
+		{name: "unsupported extension", fileName: "config.json", fileContents: "{}"},
+		{name: "invalid value", fileName: "config.yaml", fileContents: "server:\n  port: 0\n"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clearEnv(t, reflect.TypeFor[Config]())
+			t.Setenv("DATABASE_URL", "postgres://localhost/meeting_planner")
+
+			configPath := filepath.Join(t.TempDir(), testCase.fileName)
+			if writeError := os.WriteFile(configPath, []byte(testCase.fileContents), 0o600); writeError != nil {
+				t.Fatalf("failed to write config file: %v", writeError)
+			}
+
+			if _, loadError := Load(configPath); loadError == nil {
+				t.Error("Load succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		modify           func(*Config)
+		expectedProblems []string
+	}{
+		{
+			name:   "defaults",
+			modify: func(*Config) {},
+		},
+		{
+			name: "stdout tracing exporter",
+			modify: func(c *Config) {
+				c.Tracing.Exporter = "stdout"
+			},
+		},
+		{
+			name: "undocumented tracing exporter",
+			modify: func(c *Config) {
+				c.Tracing.Exporter = "console"
+			},
+			expectedProblems: []string{"tracing.exporter must be none, otlp or stdout"},
+		},
+		{
+			name: "every problem is reported",
+			modify: func(c *Config) {
+				c.Server.Port = 70000
+				c.Server.PublicURL = "localhost:8080"
+				c.Database.URL = ""
+				c.Database.MinConns = c.Database.MaxConns + 1
+				c.CORS.AllowedOrigins = nil
+			},
+			expectedProblems: []string{
+				"server.port must be between 1 and 65535",
+				"server.public_url must be an absolute http or https URL",
+				"database.url is required",
+				"database.min_conns must be between 0 and database.max_conns",
+				"cors.allowed_origins must not be empty",
+			},
+		},
+		{
+			name: "CORS route without pattern or origins",
+			modify: func(c *Config) {
+				c.CORS.Routes = []CORSRouteConfig{{}}
+			},
+			expectedProblems: []string{"cors.routes[0].pattern is required", "cors.routes[0].allowed_origins must not be empty"},
+		},
+		{
+			name: "email settings are only checked with an SMTP host",
+			modify: func(c *Config) {
+				c.Email.SMTPSecurity = "ssl"
+			},
+		},
+		{
+			name: "email settings with an SMTP host",
+			modify: func(c *Config) {
+				c.Email.SMTPHost = "smtp.example.com"
+				c.Email.SMTPSecurity = "ssl"
+			},
+			expectedProblems: []string{"email.smtp_from is required when email.smtp_host is set", "email.smtp_security must be starttls, tls or none"},
+		},
+		{
+			name: "rate limits",
+			modify: func(c *Config) {
+				c.RateLimits.Store = "redis"
+				c.RateLimits.OnStoreError = "retry"
+				c.RateLimits.AccessTokens = ratelimit.Limit{}
+			},
+			expectedProblems: []string{
+				"rate_limits.store must be memory or postgres",
+				"rate_limits.on_store_error must be allow or deny",
+				"rate_limits.access_tokens must allow at least one request per positive period",
+			},
+		},
+		{
+			name: "webhooks and deadlines",
+			modify: func(c *Config) {
+				c.Webhooks.Workers = 0
+				c.Webhooks.MaxDeliveries = -1
+				c.Webhooks.DeliveryRetention = 0
+				c.Deadlines.CheckInterval = 0
+			},
+			expectedProblems: []string{"webhooks.workers must be positive", "webhooks.max_deliveries must be positive", "webhooks.delivery_retention must be positive", "deadlines.check_interval must be positive"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			validatedConfig := validConfig()
+			testCase.modify(&validatedConfig)
+
+			validationError := validatedConfig.Validate()
+			if len(testCase.expectedProblems) == 0 {
+				if validationError != nil {
+					t.Errorf("Validate = %v, want no error", validationError)
+				}
+				return
+			}
+			if validationError == nil {
+				t.Fatalf("Validate succeeded, want %v", testCase.expectedProblems)
+			}
+
+			problems := strings.Split(validationError.Error(), "\n")
+			if !slices.Equal(problems, testCase.expectedProblems) {
+				t.Errorf("Validate = %q, want %q", problems, testCase.expectedProblems)
+			}
+		})
+	}
+}
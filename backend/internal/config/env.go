@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// applyEnv overrides the fields of target whose env tag names a variable
+// that is set and non-empty.
+func applyEnv(target *Config) error {
+	return applyEnvToStruct(reflect.ValueOf(target).Elem())
+}
+
+func applyEnvToStruct(structValue reflect.Value) error {
+	structType := structValue.Type()
+
+	for fieldIndex := 0; fieldIndex < structValue.NumField(); fieldIndex++ {
+		field := structValue.Field(fieldIndex)
+		fieldType := structType.Field(fieldIndex)
+
+		envVarName := fieldType.Tag.Get("env")
+		if envVarName == "" {
+			if field.Kind() == reflect.Struct {
+				if nestedError := applyEnvToStruct(field); nestedError != nil {
+					return nestedError
+				}
+			}
+			continue
+		}
+
+		envVarValue := os.Getenv(envVarName)
+		if envVarValue == "" {
+			continue
+		}
+
+		if settingError := setFromString(field, envVarValue); settingError != nil {
+			return fmt.Errorf("invalid %s: %w", envVarName, settingError)
+		}
+	}
+
+	return nil
+}
+
+func setFromString(field reflect.Value, stringValue string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(stringValue))
+	}
+
+	if field.Type() == durationType {
+		durationValue, parsingError := time.ParseDuration(stringValue)
+		if parsingError != nil {
+			return parsingError
+		}
+		field.SetInt(int64(durationValue))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(stringValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integerValue, parsingError := strconv.ParseInt(stringValue, 10, field.Type().Bits())
+		if parsingError != nil {
+			return parsingError
+		}
+		field.SetInt(integerValue)
+	case reflect.Bool:
+		booleanValue, parsingError := strconv.ParseBool(stringValue)
+		if parsingError != nil {
+			return parsingError
+		}
+		field.SetBool(booleanValue)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type: %s", field.Type())
+		}
+		var items []string
+		for item := range strings.SplitSeq(stringValue, ",") {
+			if trimmedItem := strings.TrimSpace(item); trimmedItem != "" {
+				items = append(items, trimmedItem)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type: %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const redactedValue = "[redacted]"
+
+// LogValue lists every setting as a nested group keyed by the file keys so
+// the effective configuration can be logged at startup. Secrets are replaced
+// with a placeholder, and the password in the database URL is masked while
+// the host and database name stay visible.
+func (c Config) LogValue() slog.Value {
+	return structLogValue(reflect.ValueOf(c))
+}
+
+func structLogValue(structValue reflect.Value) slog.Value {
+	structType := structValue.Type()
+	attributes := make([]slog.Attr, 0, structValue.NumField())
+
+	for fieldIndex := 0; fieldIndex < structValue.NumField(); fieldIndex++ {
+		field := structValue.Field(fieldIndex)
+		fieldType := structType.Field(fieldIndex)
+		key, _, _ := strings.Cut(fieldType.Tag.Get("yaml"), ",")
+
+		switch {
+		case field.Kind() == reflect.Struct && fieldType.Tag.Get("env") == "":
+			attributes = append(attributes, slog.Attr{Key: key, Value: structLogValue(field)})
+		case fieldType.Tag.Get("secret") == "true":
+			attributes = append(attributes, slog.String(key, redactSecret(field.String())))
+		default:
+			attributes = append(attributes, slog.Any(key, plainValue(field.Interface())))
+		}
+	}
+
+	return slog.GroupValue(attributes...)
+}
+
+func plainValue(value any) any {
+	switch typedValue := value.(type) {
+	case time.Duration:
+		return typedValue.String()
+	case fmt.Stringer:
+		return typedValue.String()
+	default:
+		return value
+	}
+}
+
+// redactSecret keeps an empty secret visible as empty, since that is usually
+// the interesting fact, and masks everything else. URLs keep everything but
+// the password, whether it sits in the user info or in the query.
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	parsedURL, parsingError := url.Parse(secret)
+	if parsingError != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return redactedValue
+	}
+
+	queryParams := parsedURL.Query()
+	if queryParams.Has("password") {
+		queryParams.Set("password", "xxxxx")
+		parsedURL.RawQuery = queryParams.Encode()
+	}
+	return parsedURL.Redacted()
+}
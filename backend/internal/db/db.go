@@ -3,9 +3,10 @@ package db
 import (
 	"context"
 	"fmt"
+	"meeting-planner/backend/internal/config"
 	"meeting-planner/backend/internal/db/sqlc"
-	"time"
 
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,20 +15,21 @@ type DB struct {
 	Queries *sqlc.Queries
 }
 
-func Init(ctx context.Context, databaseURL string) (*DB, error) {
-	if databaseURL == "" {
+func Init(ctx context.Context, databaseConfig config.DatabaseConfig) (*DB, error) {
+	if databaseConfig.URL == "" {
 		return nil, fmt.Errorf("DATABASE_URL is not set; set it in environment or .env")
 	}
 
-	poolConfig, configParsingError := pgxpool.ParseConfig(databaseURL)
+	poolConfig, configParsingError := pgxpool.ParseConfig(databaseConfig.URL)
 	if configParsingError != nil {
 		return nil, fmt.Errorf("Unable to parse database URL: %w", configParsingError)
 	}
-	poolConfig.MaxConns = 5
-	poolConfig.MinConns = 5
-	poolConfig.MaxConnLifetime = 30 * time.Minute
-	poolConfig.MaxConnIdleTime = 5 * time.Minute
-	poolConfig.HealthCheckPeriod = 1 * time.Minute
+	poolConfig.MaxConns = databaseConfig.MaxConns
+	poolConfig.MinConns = databaseConfig.MinConns
+	poolConfig.MaxConnLifetime = databaseConfig.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = databaseConfig.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = databaseConfig.HealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = multitracer.New(queryTracer{}, queryLogger{})
 
 	connectionPool, poolCreationError := pgxpool.NewWithConfig(ctx, poolConfig)
 	if poolCreationError != nil {
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE calendars
+  RENAME COLUMN password TO password_hash;
+
+-- +goose Down
+ALTER TABLE calendars
+  RENAME COLUMN password_hash TO password;
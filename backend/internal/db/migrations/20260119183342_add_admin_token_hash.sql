@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE calendars
+  ADD COLUMN admin_token_hash text;
+
+-- +goose Down
+ALTER TABLE calendars
+  DROP COLUMN IF EXISTS admin_token_hash;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE calendars
+  ADD COLUMN time_zone text NOT NULL DEFAULT 'UTC';
+
+-- +goose Down
+ALTER TABLE calendars
+  DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE votes
+  ADD COLUMN preference text NOT NULL DEFAULT 'yes',
+  ADD CONSTRAINT votes_preference_check CHECK (preference IN ('yes', 'if_need_be', 'no'));
+
+-- +goose Down
+ALTER TABLE votes
+  DROP CONSTRAINT IF EXISTS votes_preference_check,
+  DROP COLUMN IF EXISTS preference;
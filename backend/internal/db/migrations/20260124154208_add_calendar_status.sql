@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE calendars
+  ADD COLUMN status text NOT NULL DEFAULT 'open',
+  ADD COLUMN final_time_slot_id uuid REFERENCES calendar_time_slots(id) ON DELETE SET NULL,
+  ADD CONSTRAINT calendars_status_check CHECK (status IN ('draft', 'open', 'closed', 'finalized', 'cancelled'));
+
+-- +goose Down
+ALTER TABLE calendars
+  DROP CONSTRAINT IF EXISTS calendars_status_check,
+  DROP COLUMN IF EXISTS final_time_slot_id,
+  DROP COLUMN IF EXISTS status;
@@ -0,0 +1,69 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS participants (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  calendar_id uuid NOT NULL REFERENCES calendars(id) ON DELETE CASCADE,
+  display_name text NOT NULL,
+  edit_token_hash text,
+  created_at timestamptz DEFAULT now() NOT NULL,
+  updated_at timestamptz DEFAULT now() NOT NULL
+);
+CREATE INDEX idx_participants_calendar_id ON participants(calendar_id);
+CREATE UNIQUE INDEX idx_participants_calendar_display_name ON participants(calendar_id, display_name);
+
+-- Votes without a calendar take it from their time slot, those that cannot be
+-- tied to a calendar at all have nobody to belong to and are dropped.
+UPDATE votes
+SET calendar_id = calendar_time_slots.calendar_id
+FROM calendar_time_slots
+WHERE votes.calendar_id IS NULL
+  AND calendar_time_slots.id = votes.calendar_time_slot_id;
+
+DELETE FROM votes
+WHERE calendar_id IS NULL;
+
+-- Existing voters become participants without an edit token. The next
+-- response submitted under their name claims them, see ClaimLegacyParticipant.
+INSERT INTO participants (calendar_id, display_name, created_at)
+SELECT calendar_id, username, min(created_at)
+FROM votes
+GROUP BY calendar_id, username;
+
+ALTER TABLE votes
+  ADD COLUMN participant_id uuid REFERENCES participants(id) ON DELETE CASCADE;
+
+UPDATE votes
+SET participant_id = participants.id
+FROM participants
+WHERE participants.calendar_id = votes.calendar_id
+  AND participants.display_name = votes.username;
+
+ALTER TABLE votes
+  ALTER COLUMN participant_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_votes_user_slot;
+CREATE UNIQUE INDEX idx_votes_participant_slot ON votes(calendar_time_slot_id, participant_id);
+CREATE INDEX idx_votes_participant_id ON votes(participant_id);
+
+ALTER TABLE votes
+  DROP COLUMN username;
+
+-- +goose Down
+ALTER TABLE votes
+  ADD COLUMN username text;
+
+UPDATE votes
+SET username = participants.display_name
+FROM participants
+WHERE participants.id = votes.participant_id;
+
+ALTER TABLE votes
+  ALTER COLUMN username SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_votes_participant_id;
+DROP INDEX IF EXISTS idx_votes_participant_slot;
+CREATE UNIQUE INDEX idx_votes_user_slot ON votes(calendar_time_slot_id, username);
+
+ALTER TABLE votes
+  DROP COLUMN IF EXISTS participant_id;
+
+DROP TABLE IF EXISTS participants;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  calendar_id uuid NOT NULL REFERENCES calendars(id) ON DELETE CASCADE,
+  url text NOT NULL,
+  secret text NOT NULL,
+  event_types text[] NOT NULL DEFAULT '{}',
+  created_at timestamptz DEFAULT now() NOT NULL,
+  updated_at timestamptz DEFAULT now() NOT NULL
+);
+CREATE INDEX idx_webhooks_calendar_id ON webhooks(calendar_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_id uuid NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  delivery_id uuid NOT NULL,
+  event_type text NOT NULL,
+  payload jsonb NOT NULL,
+  attempt integer NOT NULL,
+  status_code integer,
+  error text,
+  succeeded boolean NOT NULL,
+  created_at timestamptz DEFAULT now() NOT NULL
+);
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+
+ALTER TABLE calendars
+  ADD COLUMN deadline_notified_at timestamptz;
+
+UPDATE calendars
+SET deadline_notified_at = accept_responses_until
+WHERE accept_responses_until <= now();
+
+-- +goose Down
+ALTER TABLE calendars
+  DROP COLUMN IF EXISTS deadline_notified_at;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE calendars
+  ADD COLUMN organizer_email text;
+
+-- +goose Down
+ALTER TABLE calendars
+  DROP COLUMN IF EXISTS organizer_email;
@@ -0,0 +1,18 @@
+-- Calendars created before 20260118141205_hash_calendar_passwords.sql still
+-- hold their password as plain text. pgcrypto produces $2a$ bcrypt hashes,
+-- which golang.org/x/crypto/bcrypt accepts, at its default cost of 10. An
+-- empty password never protected the calendar.
+
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+UPDATE calendars
+SET password_hash = NULL
+WHERE password_hash = '';
+
+UPDATE calendars
+SET password_hash = crypt(password_hash, gen_salt('bf', 10))
+WHERE password_hash NOT LIKE '$2%';
+
+-- +goose Down
+-- Hashed passwords cannot be turned back into plain text.
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries(created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_webhook_deliveries_created_at;
@@ -10,6 +10,11 @@ FROM calendar_time_slots
 WHERE calendar_id = $1
 ORDER BY start_date, end_date;
 
+-- name: GetCalendarTimeSlotByID :one
+SELECT *
+FROM calendar_time_slots
+WHERE id = $1;
+
 -- name: CreateCalendarTimeSlot :one
 INSERT INTO calendar_time_slots (
   calendar_id,
@@ -19,6 +24,24 @@ INSERT INTO calendar_time_slots (
 VALUES ($1, $2, $3)
 RETURNING *;
 
+-- name: CreateCalendarTimeSlots :copyfrom
+INSERT INTO calendar_time_slots (
+  id,
+  calendar_id,
+  start_date,
+  end_date
+)
+VALUES ($1, $2, $3, $4);
+
+-- name: UpdateCalendarTimeSlot :one
+UPDATE calendar_time_slots
+SET
+  start_date = $2,
+  end_date = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteCalendarTimeSlotByID :exec
 DELETE FROM calendar_time_slots
 WHERE id = $1;
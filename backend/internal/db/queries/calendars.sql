@@ -4,9 +4,13 @@ INSERT INTO calendars (
   description,
   location,
   accept_responses_until,
-  password
+  password_hash,
+  admin_token_hash,
+  time_zone,
+  status,
+  organizer_email
 )
-VALUES ($1, $2, $3, $4, $5)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING id;
 
 -- name: GetCalendarByID :one
@@ -14,6 +18,51 @@ SELECT *
 FROM calendars
 WHERE id = $1;
 
+-- name: UpdateCalendar :exec
+UPDATE calendars
+SET
+  title = $2,
+  description = $3,
+  location = $4,
+  accept_responses_until = $5,
+  password_hash = $6,
+  time_zone = $7,
+  organizer_email = $8,
+  deadline_notified_at = CASE
+    WHEN accept_responses_until IS DISTINCT FROM $5 THEN NULL
+    ELSE deadline_notified_at
+  END,
+  updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateCalendarStatus :exec
+UPDATE calendars
+SET
+  status = $2,
+  final_time_slot_id = $3,
+  updated_at = now()
+WHERE id = $1;
+
+-- name: TouchCalendar :exec
+UPDATE calendars
+SET updated_at = now()
+WHERE id = $1;
+
+-- name: ListCalendarsPastDeadline :many
+SELECT id
+FROM calendars
+WHERE status = 'open'
+  AND accept_responses_until <= now()
+  AND deadline_notified_at IS NULL
+ORDER BY accept_responses_until ASC
+LIMIT $1;
+
+-- name: MarkCalendarDeadlineNotified :execrows
+UPDATE calendars
+SET deadline_notified_at = now()
+WHERE id = $1
+  AND deadline_notified_at IS NULL;
+
 -- name: DeleteCalendarByID :exec
 DELETE FROM calendars
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
@@ -0,0 +1,40 @@
+-- name: CreateParticipant :one
+INSERT INTO participants (
+  calendar_id,
+  display_name,
+  edit_token_hash
+)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: ClaimLegacyParticipant :one
+UPDATE participants
+SET
+  edit_token_hash = $3,
+  updated_at = now()
+WHERE calendar_id = $1
+  AND display_name = $2
+  AND edit_token_hash IS NULL
+RETURNING id;
+
+-- name: GetParticipantByID :one
+SELECT *
+FROM participants
+WHERE id = $1;
+
+-- name: ListParticipantsByCalendarID :many
+SELECT *
+FROM participants
+WHERE calendar_id = $1
+ORDER BY display_name ASC;
+
+-- name: UpdateParticipantDisplayName :exec
+UPDATE participants
+SET
+  display_name = $2,
+  updated_at = now()
+WHERE id = $1;
+
+-- name: DeleteParticipantByID :exec
+DELETE FROM participants
+WHERE id = $1;
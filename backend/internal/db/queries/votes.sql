@@ -3,19 +3,28 @@ INSERT INTO votes (
   id,
   calendar_id,
   calendar_time_slot_id,
-  username,
+  participant_id,
   created_at,
-  updated_at
+  updated_at,
+  preference
 )
-VALUES ($1, $2, $3, $4, $5, $6)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: ListVotesByCalendarID :many
-SELECT id, calendar_id, calendar_time_slot_id, username, created_at
+SELECT
+  votes.id,
+  votes.calendar_id,
+  votes.calendar_time_slot_id,
+  votes.participant_id,
+  participants.display_name AS username,
+  votes.created_at,
+  votes.preference
 FROM votes
-WHERE calendar_id = $1
-ORDER BY created_at ASC;
+JOIN participants ON participants.id = votes.participant_id
+WHERE votes.calendar_id = $1
+ORDER BY votes.created_at ASC;
 
--- name: DeleteVotesByID :exec
+-- name: DeleteVotesByParticipantID :exec
 DELETE FROM votes
-WHERE id = $1;
\ No newline at end of file
+WHERE participant_id = $1;
@@ -0,0 +1,48 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  calendar_id,
+  url,
+  secret,
+  event_types
+)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
+
+-- name: GetWebhookByID :one
+SELECT *
+FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooksByCalendarID :many
+SELECT *
+FROM webhooks
+WHERE calendar_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: DeleteWebhookDeliveriesBefore :exec
+DELETE FROM webhook_deliveries
+WHERE created_at < $1;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+  webhook_id,
+  delivery_id,
+  event_type,
+  payload,
+  attempt,
+  status_code,
+  error,
+  succeeded
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListWebhookDeliveriesByWebhookID :many
+SELECT *
+FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type querySQLKey struct{}
+
+// queryLogger logs failed queries with the context they ran in, so that a
+// database error carries the request ID of the request that caused it.
+// Constraint violations are usually handled by the caller and only show up
+// at debug level.
+type queryLogger struct{}
+
+func (queryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, querySQLKey{}, data.SQL)
+}
+
+func (queryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if data.Err == nil || errors.Is(data.Err, pgx.ErrNoRows) || errors.Is(data.Err, context.Canceled) {
+		return
+	}
+
+	logLevel := slog.LevelError
+	var postgresError *pgconn.PgError
+	if errors.As(data.Err, &postgresError) && strings.HasPrefix(postgresError.Code, "23") {
+		logLevel = slog.LevelDebug
+	}
+
+	querySQL, _ := ctx.Value(querySQLKey{}).(string)
+	slog.Log(ctx, logLevel, "query failed", "query", queryName(querySQL), "error", data.Err)
+}
+
+// queryName returns the sqlc query name from the "-- name: X :kind" header,
+// or the first line of the query when there is none.
+func queryName(querySQL string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(querySQL), "\n")
+	if queryHeader, isNamed := strings.CutPrefix(firstLine, "-- name: "); isNamed {
+		name, _, _ := strings.Cut(queryHeader, " ")
+		return name
+	}
+	return firstLine
+}
@@ -41,6 +41,13 @@ func (q *Queries) CreateCalendarTimeSlot(ctx context.Context, arg CreateCalendar
 	return i, err
 }
 
+type CreateCalendarTimeSlotsParams struct {
+	ID         pgtype.UUID        `json:"id"`
+	CalendarID pgtype.UUID        `json:"calendar_id"`
+	StartDate  pgtype.Timestamptz `json:"start_date"`
+	EndDate    pgtype.Timestamptz `json:"end_date"`
+}
+
 const deleteCalendarTimeSlotByID = `-- name: DeleteCalendarTimeSlotByID :exec
 DELETE FROM calendar_time_slots
 WHERE id = $1
@@ -51,6 +58,26 @@ func (q *Queries) DeleteCalendarTimeSlotByID(ctx context.Context, id pgtype.UUID
 	return err
 }
 
+const getCalendarTimeSlotByID = `-- name: GetCalendarTimeSlotByID :one
+SELECT id, calendar_id, start_date, end_date, created_at, updated_at
+FROM calendar_time_slots
+WHERE id = $1
+`
+
+func (q *Queries) GetCalendarTimeSlotByID(ctx context.Context, id pgtype.UUID) (CalendarTimeSlot, error) {
+	row := q.db.QueryRow(ctx, getCalendarTimeSlotByID, id)
+	var i CalendarTimeSlot
+	err := row.Scan(
+		&i.ID,
+		&i.CalendarID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getCalendarTimeSlotsByCalendarID = `-- name: GetCalendarTimeSlotsByCalendarID :many
 SELECT 
   id,
@@ -90,3 +117,33 @@ func (q *Queries) GetCalendarTimeSlotsByCalendarID(ctx context.Context, calendar
 	}
 	return items, nil
 }
+
+const updateCalendarTimeSlot = `-- name: UpdateCalendarTimeSlot :one
+UPDATE calendar_time_slots
+SET
+  start_date = $2,
+  end_date = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING id, calendar_id, start_date, end_date, created_at, updated_at
+`
+
+type UpdateCalendarTimeSlotParams struct {
+	ID        pgtype.UUID        `json:"id"`
+	StartDate pgtype.Timestamptz `json:"start_date"`
+	EndDate   pgtype.Timestamptz `json:"end_date"`
+}
+
+func (q *Queries) UpdateCalendarTimeSlot(ctx context.Context, arg UpdateCalendarTimeSlotParams) (CalendarTimeSlot, error) {
+	row := q.db.QueryRow(ctx, updateCalendarTimeSlot, arg.ID, arg.StartDate, arg.EndDate)
+	var i CalendarTimeSlot
+	err := row.Scan(
+		&i.ID,
+		&i.CalendarID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
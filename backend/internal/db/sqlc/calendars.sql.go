@@ -17,9 +17,13 @@ INSERT INTO calendars (
   description,
   location,
   accept_responses_until,
-  password
+  password_hash,
+  admin_token_hash,
+  time_zone,
+  status,
+  organizer_email
 )
-VALUES ($1, $2, $3, $4, $5)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING id
 `
 
@@ -28,7 +32,11 @@ type CreateCalendarParams struct {
 	Description          *string            `json:"description"`
 	Location             *string            `json:"location"`
 	AcceptResponsesUntil pgtype.Timestamptz `json:"accept_responses_until"`
-	Password             *string            `json:"password"`
+	PasswordHash         *string            `json:"password_hash"`
+	AdminTokenHash       *string            `json:"admin_token_hash"`
+	TimeZone             string             `json:"time_zone"`
+	Status               string             `json:"status"`
+	OrganizerEmail       *string            `json:"organizer_email"`
 }
 
 func (q *Queries) CreateCalendar(ctx context.Context, arg CreateCalendarParams) (pgtype.UUID, error) {
@@ -37,7 +45,11 @@ func (q *Queries) CreateCalendar(ctx context.Context, arg CreateCalendarParams)
 		arg.Description,
 		arg.Location,
 		arg.AcceptResponsesUntil,
-		arg.Password,
+		arg.PasswordHash,
+		arg.AdminTokenHash,
+		arg.TimeZone,
+		arg.Status,
+		arg.OrganizerEmail,
 	)
 	var id pgtype.UUID
 	err := row.Scan(&id)
@@ -55,7 +67,7 @@ func (q *Queries) DeleteCalendarByID(ctx context.Context, id pgtype.UUID) error
 }
 
 const getCalendarByID = `-- name: GetCalendarByID :one
-SELECT id, title, description, location, accept_responses_until, password, created_at, updated_at
+SELECT id, title, description, location, accept_responses_until, password_hash, created_at, updated_at, admin_token_hash, time_zone, status, final_time_slot_id, deadline_notified_at, organizer_email
 FROM calendars
 WHERE id = $1
 `
@@ -69,9 +81,134 @@ func (q *Queries) GetCalendarByID(ctx context.Context, id pgtype.UUID) (Calendar
 		&i.Description,
 		&i.Location,
 		&i.AcceptResponsesUntil,
-		&i.Password,
+		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AdminTokenHash,
+		&i.TimeZone,
+		&i.Status,
+		&i.FinalTimeSlotID,
+		&i.DeadlineNotifiedAt,
+		&i.OrganizerEmail,
 	)
 	return i, err
 }
+
+const listCalendarsPastDeadline = `-- name: ListCalendarsPastDeadline :many
+SELECT id
+FROM calendars
+WHERE status = 'open'
+  AND accept_responses_until <= now()
+  AND deadline_notified_at IS NULL
+ORDER BY accept_responses_until ASC
+LIMIT $1
+`
+
+func (q *Queries) ListCalendarsPastDeadline(ctx context.Context, limit int32) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listCalendarsPastDeadline, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.UUID{}
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markCalendarDeadlineNotified = `-- name: MarkCalendarDeadlineNotified :execrows
+UPDATE calendars
+SET deadline_notified_at = now()
+WHERE id = $1
+  AND deadline_notified_at IS NULL
+`
+
+func (q *Queries) MarkCalendarDeadlineNotified(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markCalendarDeadlineNotified, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const touchCalendar = `-- name: TouchCalendar :exec
+UPDATE calendars
+SET updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchCalendar(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchCalendar, id)
+	return err
+}
+
+const updateCalendar = `-- name: UpdateCalendar :exec
+UPDATE calendars
+SET
+  title = $2,
+  description = $3,
+  location = $4,
+  accept_responses_until = $5,
+  password_hash = $6,
+  time_zone = $7,
+  organizer_email = $8,
+  deadline_notified_at = CASE
+    WHEN accept_responses_until IS DISTINCT FROM $5 THEN NULL
+    ELSE deadline_notified_at
+  END,
+  updated_at = now()
+WHERE id = $1
+`
+
+type UpdateCalendarParams struct {
+	ID                   pgtype.UUID        `json:"id"`
+	Title                string             `json:"title"`
+	Description          *string            `json:"description"`
+	Location             *string            `json:"location"`
+	AcceptResponsesUntil pgtype.Timestamptz `json:"accept_responses_until"`
+	PasswordHash         *string            `json:"password_hash"`
+	TimeZone             string             `json:"time_zone"`
+	OrganizerEmail       *string            `json:"organizer_email"`
+}
+
+func (q *Queries) UpdateCalendar(ctx context.Context, arg UpdateCalendarParams) error {
+	_, err := q.db.Exec(ctx, updateCalendar,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Location,
+		arg.AcceptResponsesUntil,
+		arg.PasswordHash,
+		arg.TimeZone,
+		arg.OrganizerEmail,
+	)
+	return err
+}
+
+const updateCalendarStatus = `-- name: UpdateCalendarStatus :exec
+UPDATE calendars
+SET
+  status = $2,
+  final_time_slot_id = $3,
+  updated_at = now()
+WHERE id = $1
+`
+
+type UpdateCalendarStatusParams struct {
+	ID              pgtype.UUID `json:"id"`
+	Status          string      `json:"status"`
+	FinalTimeSlotID pgtype.UUID `json:"final_time_slot_id"`
+}
+
+func (q *Queries) UpdateCalendarStatus(ctx context.Context, arg UpdateCalendarStatusParams) error {
+	_, err := q.db.Exec(ctx, updateCalendarStatus, arg.ID, arg.Status, arg.FinalTimeSlotID)
+	return err
+}
@@ -14,9 +14,15 @@ type Calendar struct {
 	Description          *string            `json:"description"`
 	Location             *string            `json:"location"`
 	AcceptResponsesUntil pgtype.Timestamptz `json:"accept_responses_until"`
-	Password             *string            `json:"password"`
+	PasswordHash         *string            `json:"password_hash"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	AdminTokenHash       *string            `json:"admin_token_hash"`
+	TimeZone             string             `json:"time_zone"`
+	Status               string             `json:"status"`
+	FinalTimeSlotID      pgtype.UUID        `json:"final_time_slot_id"`
+	DeadlineNotifiedAt   pgtype.Timestamptz `json:"deadline_notified_at"`
+	OrganizerEmail       *string            `json:"organizer_email"`
 }
 
 type CalendarTimeSlot struct {
@@ -28,11 +34,51 @@ type CalendarTimeSlot struct {
 	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
 }
 
+type Participant struct {
+	ID            pgtype.UUID        `json:"id"`
+	CalendarID    pgtype.UUID        `json:"calendar_id"`
+	DisplayName   string             `json:"display_name"`
+	EditTokenHash *string            `json:"edit_token_hash"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string             `json:"key"`
+	Tokens    float64            `json:"tokens"`
+	Allowed   bool               `json:"allowed"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Vote struct {
 	ID                 pgtype.UUID        `json:"id"`
 	CalendarID         pgtype.UUID        `json:"calendar_id"`
 	CalendarTimeSlotID pgtype.UUID        `json:"calendar_time_slot_id"`
-	Username           string             `json:"username"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	Preference         string             `json:"preference"`
+	ParticipantID      pgtype.UUID        `json:"participant_id"`
+}
+
+type Webhook struct {
+	ID         pgtype.UUID        `json:"id"`
+	CalendarID pgtype.UUID        `json:"calendar_id"`
+	Url        string             `json:"url"`
+	Secret     string             `json:"secret"`
+	EventTypes []string           `json:"event_types"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID         pgtype.UUID        `json:"id"`
+	WebhookID  pgtype.UUID        `json:"webhook_id"`
+	DeliveryID pgtype.UUID        `json:"delivery_id"`
+	EventType  string             `json:"event_type"`
+	Payload    []byte             `json:"payload"`
+	Attempt    int32              `json:"attempt"`
+	StatusCode *int32             `json:"status_code"`
+	Error      *string            `json:"error"`
+	Succeeded  bool               `json:"succeeded"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
@@ -11,15 +11,38 @@ import (
 )
 
 type Querier interface {
+	ClaimLegacyParticipant(ctx context.Context, arg ClaimLegacyParticipantParams) (pgtype.UUID, error)
 	CreateCalendar(ctx context.Context, arg CreateCalendarParams) (pgtype.UUID, error)
 	CreateCalendarTimeSlot(ctx context.Context, arg CreateCalendarTimeSlotParams) (CalendarTimeSlot, error)
+	CreateCalendarTimeSlots(ctx context.Context, arg []CreateCalendarTimeSlotsParams) (int64, error)
+	CreateParticipant(ctx context.Context, arg CreateParticipantParams) (pgtype.UUID, error)
 	CreateVote(ctx context.Context, arg CreateVoteParams) (Vote, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (pgtype.UUID, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
 	DeleteCalendarByID(ctx context.Context, id pgtype.UUID) error
 	DeleteCalendarTimeSlotByID(ctx context.Context, id pgtype.UUID) error
-	DeleteVotesByID(ctx context.Context, id pgtype.UUID) error
+	DeleteParticipantByID(ctx context.Context, id pgtype.UUID) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) error
+	DeleteVotesByParticipantID(ctx context.Context, participantID pgtype.UUID) error
+	DeleteWebhookByID(ctx context.Context, id pgtype.UUID) error
+	DeleteWebhookDeliveriesBefore(ctx context.Context, createdAt pgtype.Timestamptz) error
 	GetCalendarByID(ctx context.Context, id pgtype.UUID) (Calendar, error)
+	GetCalendarTimeSlotByID(ctx context.Context, id pgtype.UUID) (CalendarTimeSlot, error)
 	GetCalendarTimeSlotsByCalendarID(ctx context.Context, calendarID pgtype.UUID) ([]CalendarTimeSlot, error)
+	GetParticipantByID(ctx context.Context, id pgtype.UUID) (Participant, error)
+	GetWebhookByID(ctx context.Context, id pgtype.UUID) (Webhook, error)
+	ListCalendarsPastDeadline(ctx context.Context, limit int32) ([]pgtype.UUID, error)
+	ListParticipantsByCalendarID(ctx context.Context, calendarID pgtype.UUID) ([]Participant, error)
 	ListVotesByCalendarID(ctx context.Context, calendarID pgtype.UUID) ([]ListVotesByCalendarIDRow, error)
+	ListWebhookDeliveriesByWebhookID(ctx context.Context, arg ListWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error)
+	ListWebhooksByCalendarID(ctx context.Context, calendarID pgtype.UUID) ([]Webhook, error)
+	MarkCalendarDeadlineNotified(ctx context.Context, id pgtype.UUID) (int64, error)
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error)
+	TouchCalendar(ctx context.Context, id pgtype.UUID) error
+	UpdateCalendar(ctx context.Context, arg UpdateCalendarParams) error
+	UpdateCalendarStatus(ctx context.Context, arg UpdateCalendarStatusParams) error
+	UpdateCalendarTimeSlot(ctx context.Context, arg UpdateCalendarTimeSlotParams) (CalendarTimeSlot, error)
+	UpdateParticipantDisplayName(ctx context.Context, arg UpdateParticipantDisplayNameParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -16,21 +16,23 @@ INSERT INTO votes (
   id,
   calendar_id,
   calendar_time_slot_id,
-  username,
+  participant_id,
   created_at,
-  updated_at
+  updated_at,
+  preference
 )
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, calendar_id, calendar_time_slot_id, username, created_at, updated_at
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, calendar_id, calendar_time_slot_id, created_at, updated_at, preference, participant_id
 `
 
 type CreateVoteParams struct {
 	ID                 pgtype.UUID        `json:"id"`
 	CalendarID         pgtype.UUID        `json:"calendar_id"`
 	CalendarTimeSlotID pgtype.UUID        `json:"calendar_time_slot_id"`
-	Username           string             `json:"username"`
+	ParticipantID      pgtype.UUID        `json:"participant_id"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	Preference         string             `json:"preference"`
 }
 
 func (q *Queries) CreateVote(ctx context.Context, arg CreateVoteParams) (Vote, error) {
@@ -38,45 +40,57 @@ func (q *Queries) CreateVote(ctx context.Context, arg CreateVoteParams) (Vote, e
 		arg.ID,
 		arg.CalendarID,
 		arg.CalendarTimeSlotID,
-		arg.Username,
+		arg.ParticipantID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Preference,
 	)
 	var i Vote
 	err := row.Scan(
 		&i.ID,
 		&i.CalendarID,
 		&i.CalendarTimeSlotID,
-		&i.Username,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Preference,
+		&i.ParticipantID,
 	)
 	return i, err
 }
 
-const deleteVotesByID = `-- name: DeleteVotesByID :exec
+const deleteVotesByParticipantID = `-- name: DeleteVotesByParticipantID :exec
 DELETE FROM votes
-WHERE id = $1
+WHERE participant_id = $1
 `
 
-func (q *Queries) DeleteVotesByID(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteVotesByID, id)
+func (q *Queries) DeleteVotesByParticipantID(ctx context.Context, participantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteVotesByParticipantID, participantID)
 	return err
 }
 
 const listVotesByCalendarID = `-- name: ListVotesByCalendarID :many
-SELECT id, calendar_id, calendar_time_slot_id, username, created_at
+SELECT
+  votes.id,
+  votes.calendar_id,
+  votes.calendar_time_slot_id,
+  votes.participant_id,
+  participants.display_name AS username,
+  votes.created_at,
+  votes.preference
 FROM votes
-WHERE calendar_id = $1
-ORDER BY created_at ASC
+JOIN participants ON participants.id = votes.participant_id
+WHERE votes.calendar_id = $1
+ORDER BY votes.created_at ASC
 `
 
 type ListVotesByCalendarIDRow struct {
 	ID                 pgtype.UUID        `json:"id"`
 	CalendarID         pgtype.UUID        `json:"calendar_id"`
 	CalendarTimeSlotID pgtype.UUID        `json:"calendar_time_slot_id"`
+	ParticipantID      pgtype.UUID        `json:"participant_id"`
 	Username           string             `json:"username"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	Preference         string             `json:"preference"`
 }
 
 func (q *Queries) ListVotesByCalendarID(ctx context.Context, calendarID pgtype.UUID) ([]ListVotesByCalendarIDRow, error) {
@@ -92,8 +106,10 @@ func (q *Queries) ListVotesByCalendarID(ctx context.Context, calendarID pgtype.U
 			&i.ID,
 			&i.CalendarID,
 			&i.CalendarTimeSlotID,
+			&i.ParticipantID,
 			&i.Username,
 			&i.CreatedAt,
+			&i.Preference,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,168 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const subscriberBufferSize = 32
+
+const (
+	TypeCalendarUpdated   = "calendar_updated"
+	TypeCalendarDeleted   = "calendar_deleted"
+	TypeStatusChanged     = "status_changed"
+	TypeCalendarFinalized = "calendar_finalized"
+	TypeDeadlineReached   = "deadline_reached"
+	TypeTimeSlotsCreated  = "time_slots_created"
+	TypeTimeSlotUpdated   = "time_slot_updated"
+	TypeTimeSlotDeleted   = "time_slot_deleted"
+	TypeVoteCreated       = "vote_created"
+	TypeVoteChanged       = "vote_changed"
+	TypeVoteWithdrawn     = "vote_withdrawn"
+)
+
+var ErrHubClosed = errors.New("event hub closed")
+
+type Event struct {
+	ID         uint64
+	Type       string
+	CalendarID pgtype.UUID
+	OccurredAt time.Time
+	Data       map[string]any
+}
+
+type Subscription struct {
+	Events     <-chan Event
+	events     chan Event
+	calendarID pgtype.UUID
+	hub        *Hub
+}
+
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub fans out calendar events to in-process subscribers. Publishing never
+// blocks: a subscriber whose buffer is full is dropped and its channel
+// closed, so the client reconnects and reloads instead of missing updates.
+type Hub struct {
+	mutex       sync.Mutex
+	subscribers map[pgtype.UUID]map[*Subscription]struct{}
+	listeners   []func(Event)
+	nextEventID uint64
+	closed      bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[pgtype.UUID]map[*Subscription]struct{}),
+	}
+}
+
+func (h *Hub) Subscribe(calendarID pgtype.UUID) (*Subscription, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	events := make(chan Event, subscriberBufferSize)
+	subscription := &Subscription{
+		Events:     events,
+		events:     events,
+		calendarID: calendarID,
+		hub:        h,
+	}
+
+	if h.subscribers[calendarID] == nil {
+		h.subscribers[calendarID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[calendarID][subscription] = struct{}{}
+
+	return subscription, nil
+}
+
+// AddListener registers a callback that receives every published event,
+// whatever its calendar. It runs while the hub is locked, so it must hand the
+// event off without blocking and must not call back into the hub.
+func (h *Hub) AddListener(listener func(Event)) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.listeners = append(h.listeners, listener)
+}
+
+func (h *Hub) Publish(calendarID pgtype.UUID, eventType string, data map[string]any) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.nextEventID++
+	event := Event{
+		ID:         h.nextEventID,
+		Type:       eventType,
+		CalendarID: calendarID,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}
+
+	for _, listener := range h.listeners {
+		listener(event)
+	}
+
+	for subscription := range h.subscribers[calendarID] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.removeLocked(subscription)
+		}
+	}
+}
+
+// Close ends every subscription and rejects new ones. It is safe to call
+// more than once.
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+
+	for _, calendarSubscribers := range h.subscribers {
+		for subscription := range calendarSubscribers {
+			h.removeLocked(subscription)
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(subscription *Subscription) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.removeLocked(subscription)
+}
+
+func (h *Hub) removeLocked(subscription *Subscription) {
+	calendarSubscribers, exists := h.subscribers[subscription.calendarID]
+	if !exists {
+		return
+	}
+	if _, subscribed := calendarSubscribers[subscription]; !subscribed {
+		return
+	}
+
+	delete(calendarSubscribers, subscription)
+	if len(calendarSubscribers) == 0 {
+		delete(h.subscribers, subscription.calendarID)
+	}
+	close(subscription.events)
+}
@@ -0,0 +1,238 @@
+package frontend
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	indexFile = "index.html"
+
+	immutableCacheControl  = "public, max-age=31536000, immutable"
+	revalidateCacheControl = "no-cache"
+)
+
+// hashedAssetName matches the file names Vite gives the bundles it writes to
+// assets/, such as index-Ct4TLqzy.js. Their content never changes under the
+// same name, so browsers may cache them forever.
+var hashedAssetName = regexp.MustCompile(`^assets/.*-[A-Za-z0-9_-]{8,}\.[A-Za-z0-9]+$`)
+
+// contentEncodings lists the precompressed variants in order of preference,
+// keyed by the Content-Encoding they are served with.
+var contentEncodings = []struct {
+	name      string
+	extension string
+}{
+	{name: "br", extension: ".br"},
+	{name: "gzip", extension: ".gz"},
+}
+
+type asset struct {
+	name     string
+	content  []byte
+	etag     string
+	modTime  time.Time
+	variants map[string]*asset
+}
+
+// Server serves the built client. Files are looked up in an index built once
+// by New; NewLive reads them on every request so a development build on disk
+// is picked up without a restart.
+type Server struct {
+	files  fs.FS
+	assets map[string]*asset
+}
+
+func New(files fs.FS) (*Server, error) {
+	assets := map[string]*asset{}
+
+	walkingError := fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, entryError error) error {
+		if entryError != nil {
+			return entryError
+		}
+		if entry.IsDir() || isVariant(name) {
+			return nil
+		}
+
+		loadedAsset, loadingError := loadAsset(files, name)
+		if loadingError != nil {
+			return loadingError
+		}
+		assets[name] = loadedAsset
+		return nil
+	})
+	if walkingError != nil {
+		return nil, fmt.Errorf("failed to index frontend files: %w", walkingError)
+	}
+
+	if _, hasIndex := assets[indexFile]; !hasIndex {
+		return nil, fmt.Errorf("frontend files have no %s", indexFile)
+	}
+
+	return &Server{files: files, assets: assets}, nil
+}
+
+func NewLive(files fs.FS) *Server {
+	return &Server{files: files}
+}
+
+func isVariant(name string) bool {
+	for _, encoding := range contentEncodings {
+		if strings.HasSuffix(name, encoding.extension) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadAsset(files fs.FS, name string) (*asset, error) {
+	loadedAsset, loadingError := readAsset(files, name)
+	if loadingError != nil {
+		return nil, loadingError
+	}
+
+	for _, encoding := range contentEncodings {
+		variant, variantError := readAsset(files, name+encoding.extension)
+		if errors.Is(variantError, fs.ErrNotExist) {
+			continue
+		}
+		if variantError != nil {
+			return nil, variantError
+		}
+
+		if loadedAsset.variants == nil {
+			loadedAsset.variants = map[string]*asset{}
+		}
+		loadedAsset.variants[encoding.name] = variant
+	}
+
+	return loadedAsset, nil
+}
+
+func readAsset(files fs.FS, name string) (*asset, error) {
+	fileInfo, statError := fs.Stat(files, name)
+	if statError != nil {
+		return nil, statError
+	}
+	if fileInfo.IsDir() {
+		return nil, fs.ErrNotExist
+	}
+
+	content, readError := fs.ReadFile(files, name)
+	if readError != nil {
+		return nil, readError
+	}
+
+	contentHash := sha256.Sum256(content)
+	return &asset{
+		name:    name,
+		content: content,
+		etag:    `"` + hex.EncodeToString(contentHash[:8]) + `"`,
+		modTime: fileInfo.ModTime(),
+	}, nil
+}
+
+func (s *Server) lookup(name string) (*asset, error) {
+	if s.assets == nil {
+		return loadAsset(s.files, name)
+	}
+	if foundAsset, hasAsset := s.assets[name]; hasAsset {
+		return foundAsset, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+// ServeHTTP serves the requested file or, for paths without an extension,
+// index.html so the client router can handle them. A missing file with an
+// extension is a 404 rather than index.html, which a browser would otherwise
+// cache in place of a script or stylesheet.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestedPath := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if requestedPath == "" {
+		requestedPath = indexFile
+	}
+
+	requestedAsset, lookupError := s.lookup(requestedPath)
+	if errors.Is(lookupError, fs.ErrNotExist) && path.Ext(requestedPath) == "" {
+		requestedAsset, lookupError = s.lookup(indexFile)
+	}
+	if errors.Is(lookupError, fs.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+	if lookupError != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	s.serveAsset(w, r, requestedAsset)
+}
+
+func (s *Server) serveAsset(w http.ResponseWriter, r *http.Request, requestedAsset *asset) {
+	if hashedAssetName.MatchString(requestedAsset.name) {
+		w.Header().Set("Cache-Control", immutableCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", revalidateCacheControl)
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(requestedAsset.name)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	servedAsset := requestedAsset
+	if len(requestedAsset.variants) > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		acceptEncodingHeader := r.Header.Get("Accept-Encoding")
+		for _, encoding := range contentEncodings {
+			variant, hasVariant := requestedAsset.variants[encoding.name]
+			if hasVariant && acceptsEncoding(acceptEncodingHeader, encoding.name) {
+				w.Header().Set("Content-Encoding", encoding.name)
+				servedAsset = variant
+				break
+			}
+		}
+	}
+
+	w.Header().Set("ETag", servedAsset.etag)
+	http.ServeContent(w, r, requestedAsset.name, servedAsset.modTime, bytes.NewReader(servedAsset.content))
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows coding,
+// either by name or through "*", with a quality value above zero. An entry
+// naming the coding takes precedence over "*".
+func acceptsEncoding(acceptEncodingHeader string, coding string) bool {
+	wildcardAccepted := false
+	for headerEntry := range strings.SplitSeq(acceptEncodingHeader, ",") {
+		entryCoding, entryParams, _ := strings.Cut(headerEntry, ";")
+		entryCoding = strings.TrimSpace(entryCoding)
+
+		switch {
+		case strings.EqualFold(entryCoding, coding):
+			return hasPositiveQuality(entryParams)
+		case entryCoding == "*":
+			wildcardAccepted = hasPositiveQuality(entryParams)
+		}
+	}
+	return wildcardAccepted
+}
+
+func hasPositiveQuality(entryParams string) bool {
+	qualityText, hasQuality := strings.CutPrefix(strings.TrimSpace(entryParams), "q=")
+	if !hasQuality {
+		return true
+	}
+	quality, parsingError := strconv.ParseFloat(qualityText, 64)
+	return parsingError == nil && quality > 0
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const adminTokenHeader = "X-Admin-Token"
+
+// authorizeCalendarAccess accepts the access token of a password protected
+// calendar but never the password itself, so that passwords can only be
+// guessed through the rate limited access token endpoint.
+func (h *Handler) authorizeCalendarAccess(w http.ResponseWriter, r *http.Request, calendarID pgtype.UUID) bool {
+	credentials := services.CalendarCredentials{
+		AccessToken: bearerToken(r),
+		AdminToken:  r.Header.Get(adminTokenHeader),
+	}
+
+	if accessError := h.CalendarService.AuthorizeCalendarAccess(r.Context(), calendarID, credentials); accessError != nil {
+		respondServiceError(w, r, accessError, "Failed to authorize calendar access")
+		return false
+	}
+
+	return true
+}
+
+// authorizeCalendarFeed also accepts the feed_token query parameter, which
+// calendar apps keep sending with the subscription URL.
+func (h *Handler) authorizeCalendarFeed(w http.ResponseWriter, r *http.Request, calendarID pgtype.UUID) bool {
+	credentials := services.CalendarCredentials{
+		AccessToken: bearerToken(r),
+		AdminToken:  r.Header.Get(adminTokenHeader),
+		FeedToken:   r.URL.Query().Get("feed_token"),
+	}
+
+	if accessError := h.CalendarService.AuthorizeCalendarFeed(r.Context(), calendarID, credentials); accessError != nil {
+		respondServiceError(w, r, accessError, "Failed to authorize calendar access")
+		return false
+	}
+
+	return true
+}
+
+func (h *Handler) authorizeCalendarAdmin(w http.ResponseWriter, r *http.Request, calendarID pgtype.UUID) bool {
+	if adminError := h.CalendarService.AuthorizeCalendarAdmin(r.Context(), calendarID, r.Header.Get(adminTokenHeader)); adminError != nil {
+		respondServiceError(w, r, adminError, "Failed to authorize calendar admin")
+		return false
+	}
+
+	return true
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return r.URL.Query().Get("access_token")
+	}
+	return strings.TrimSpace(token)
+}
+
+type CreateAccessTokenRequest struct {
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+type CreateAccessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresAt   string `json:"expires_at"`
+	// FeedToken goes in the feed_token query parameter of calendar.ics so
+	// that calendar apps can subscribe to the calendar.
+	FeedToken          string `json:"feed_token"`
+	FeedTokenExpiresAt string `json:"feed_token_expires_at"`
+}
+
+func (h *Handler) CreateAccessTokenEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	var requestBody CreateAccessTokenRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	accessToken, exchangeError := h.CalendarService.ExchangePassword(r.Context(), calendarUUID, requestBody.Password)
+	if exchangeError != nil {
+		respondServiceError(w, r, exchangeError, "Failed to create access token")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, CreateAccessTokenResponse{
+		AccessToken:        accessToken.Token,
+		ExpiresAt:          accessToken.ExpiresAt.Format(time.RFC3339),
+		FeedToken:          accessToken.FeedToken,
+		FeedTokenExpiresAt: accessToken.FeedTokenExpiresAt.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxICalendarUploadBytes = 1 << 20
+
+type ImportAvailabilityQuery struct {
+	Username      string `query:"username" validate:"omitempty,min=1,max=128"`
+	ParticipantID string `query:"participant_id" validate:"omitempty,uuid"`
+	Apply         bool   `query:"apply"`
+}
+
+type ImportAvailabilityResponse struct {
+	SuggestedTimeSlotIDs []string `json:"suggested_time_slot_ids"`
+	Applied              bool     `json:"applied"`
+	ParticipantID        string   `json:"participant_id,omitempty"`
+	EditToken            string   `json:"edit_token,omitempty"`
+}
+
+func (h *Handler) ImportAvailabilityEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	var requestQuery ImportAvailabilityQuery
+
+	if parsingError := ParseRequest(r, RequestOptions{Query: &requestQuery}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	if !h.authorizeCalendarAccess(w, r, calendarUUID) {
+		return
+	}
+
+	var participantUUID pgtype.UUID
+	if requestQuery.Apply {
+		if requestQuery.ParticipantID == "" && strings.TrimSpace(requestQuery.Username) == "" {
+			RespondError(w, http.StatusBadRequest, "username or participant_id is required to apply votes")
+			return
+		}
+
+		if requestQuery.ParticipantID != "" {
+			var participantUUIDError error
+			participantUUID, participantUUIDError = utils.StringToUUID(requestQuery.ParticipantID)
+			if participantUUIDError != nil {
+				RespondError(w, http.StatusBadRequest, "Invalid participant ID")
+				return
+			}
+
+			if !h.authorizeParticipant(w, r, calendarUUID, participantUUID) {
+				return
+			}
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxICalendarUploadBytes)
+
+	icalendarReader, readerError := uploadedICalendar(r)
+	if readerError != nil {
+		if isUploadTooLarge(readerError) {
+			RespondError(w, http.StatusRequestEntityTooLarge, "The iCalendar file cannot exceed 1 MiB")
+			return
+		}
+		RespondError(w, http.StatusBadRequest, readerError.Error())
+		return
+	}
+	defer icalendarReader.Close()
+
+	suggestedSlotIDs, suggestionError := h.CalendarService.SuggestTimeSlotsFromICalendar(r.Context(), calendarUUID, icalendarReader)
+	if suggestionError != nil {
+		if isUploadTooLarge(suggestionError) {
+			RespondError(w, http.StatusRequestEntityTooLarge, "The iCalendar file cannot exceed 1 MiB")
+			return
+		}
+		respondServiceError(w, r, suggestionError, "Failed to import availability")
+		return
+	}
+
+	response := ImportAvailabilityResponse{
+		SuggestedTimeSlotIDs: uuidsToStrings(suggestedSlotIDs),
+	}
+
+	if requestQuery.Apply {
+		votes := make([]services.VoteInput, 0, len(suggestedSlotIDs))
+		for _, slotID := range suggestedSlotIDs {
+			votes = append(votes, services.VoteInput{
+				TimeSlotID: slotID,
+				Preference: services.PreferenceYes,
+			})
+		}
+
+		if participantUUID.Valid {
+			updateError := h.CalendarService.UpdateResponse(r.Context(), services.UpdateResponseInput{
+				CalendarID:    calendarUUID,
+				ParticipantID: participantUUID,
+				Votes:         votes,
+			})
+			if updateError != nil {
+				respondServiceError(w, r, updateError, "Failed to save votes")
+				return
+			}
+			response.ParticipantID = requestQuery.ParticipantID
+		} else {
+			submittedResponse, submissionError := h.CalendarService.SubmitResponse(r.Context(), services.SubmitResponseInput{
+				CalendarID:  calendarUUID,
+				DisplayName: strings.TrimSpace(requestQuery.Username),
+				Votes:       votes,
+			})
+			if submissionError != nil {
+				respondServiceError(w, r, submissionError, "Failed to save votes")
+				return
+			}
+			response.ParticipantID = utils.UUIDToString(submittedResponse.ParticipantID)
+			response.EditToken = submittedResponse.EditToken
+		}
+		response.Applied = true
+	}
+
+	RespondJSON(w, http.StatusOK, response)
+}
+
+func uploadedICalendar(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	uploadedFile, _, fileError := r.FormFile("file")
+	if fileError != nil {
+		return nil, fileError
+	}
+	return uploadedFile, nil
+}
+
+func isUploadTooLarge(uploadError error) bool {
+	var maxBytesError *http.MaxBytesError
+	return errors.As(uploadError, &maxBytesError)
+}
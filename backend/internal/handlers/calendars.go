@@ -8,15 +8,21 @@ import (
 )
 
 type CreateCalendarRequest struct {
-	Title                string  `json:"title" validate:"required,min=3,max=256"`
-	Description          *string `json:"description,omitempty" validate:"omitempty,max=1024"`
-	Location             *string `json:"location,omitempty" validate:"omitempty,max=512"`
-	AcceptResponsesUntil *string `json:"accept_responses_until,omitempty" validate:"omitempty,rfc3339"`
-	Password 					   *string `json:"password,omitempty" validate:"omitempty,min=3,max=128"`
+	Title                string              `json:"title" validate:"required,min=3,max=256"`
+	Description          *string             `json:"description,omitempty" validate:"omitempty,max=1024"`
+	Location             *string             `json:"location,omitempty" validate:"omitempty,max=512"`
+	AcceptResponsesUntil *string             `json:"accept_responses_until,omitempty" validate:"omitempty,rfc3339"`
+	Password             *string             `json:"password,omitempty" validate:"omitempty,min=3,max=72"`
+	TimeZone             string              `json:"time_zone,omitempty" validate:"omitempty,timezone"`
+	Status               string              `json:"status,omitempty" validate:"omitempty,oneof=draft open"`
+	OrganizerEmail       *string             `json:"organizer_email,omitempty" validate:"omitempty,email,max=254"`
+	TimeSlots            []CalendarTimeSlots `json:"time_slots,omitempty" validate:"omitempty,dive,required"`
 }
 
 type CreateCalendarResponse struct {
-	ID string `json:"id"`
+	ID          string   `json:"id"`
+	AdminToken  string   `json:"admin_token"`
+	TimeSlotIDs []string `json:"time_slot_ids"`
 }
 
 func (h *Handler) CreateCalendarEndpoint(w http.ResponseWriter, r *http.Request) {
@@ -28,9 +34,13 @@ func (h *Handler) CreateCalendarEndpoint(w http.ResponseWriter, r *http.Request)
 	}
 
 	serviceInput := services.CreateCalendarInput{
-		Title:       requestBody.Title,
-		Description: requestBody.Description,
-		Location:    requestBody.Location,
+		Title:          requestBody.Title,
+		Description:    requestBody.Description,
+		Location:       requestBody.Location,
+		Password:       requestBody.Password,
+		TimeZone:       requestBody.TimeZone,
+		Status:         services.CalendarStatus(requestBody.Status),
+		OrganizerEmail: requestBody.OrganizerEmail,
 	}
 
 	if requestBody.AcceptResponsesUntil != nil {
@@ -42,68 +52,294 @@ func (h *Handler) CreateCalendarEndpoint(w http.ResponseWriter, r *http.Request)
 		serviceInput.AcceptResponsesUntil = &parsedTime
 	}
 
-	calendarID, creationError := h.CalendarService.CreateCalendar(r.Context(), serviceInput)
+	for _, slot := range requestBody.TimeSlots {
+		timeSlot, slotError := parseTimeSlot(slot)
+		if slotError != nil {
+			RespondError(w, http.StatusBadRequest, slotError.Error())
+			return
+		}
+		serviceInput.TimeSlots = append(serviceInput.TimeSlots, timeSlot)
+	}
+
+	createdCalendar, creationError := h.CalendarService.CreateCalendar(r.Context(), serviceInput)
 	if creationError != nil {
-		RespondError(w, http.StatusInternalServerError, "Failed to create calendar")
+		respondServiceError(w, r, creationError, "Failed to create calendar")
 		return
 	}
 
 	RespondJSON(w, http.StatusCreated, CreateCalendarResponse{
-		ID: utils.UUIDToString(calendarID),
+		ID:          utils.UUIDToString(createdCalendar.ID),
+		AdminToken:  createdCalendar.AdminToken,
+		TimeSlotIDs: uuidsToStrings(createdCalendar.TimeSlotIDs),
 	})
 }
 
-type CalendarTimeSlots struct {
-	StartDate string `json:"start_date" validate:"required,rfc3339"`
-	EndDate   string `json:"end_date" validate:"required,rfc3339"`
+type TimeSlotVoteResponse struct {
+	ParticipantID string `json:"participant_id"`
+	Username      string `json:"username"`
+	Preference    string `json:"preference"`
 }
 
-type CreateCalendarTimeSlotsRequest struct {
-	TimeSlots []CalendarTimeSlots `json:"time_slots" validate:"required,dive,required"`
+type TimeSlotResponse struct {
+	ID            string                 `json:"id"`
+	StartDate     string                 `json:"start_date"`
+	EndDate       string                 `json:"end_date"`
+	VoteCount     int                    `json:"vote_count"`
+	Voters        []string               `json:"voters"`
+	YesCount      int                    `json:"yes_count"`
+	IfNeedBeCount int                    `json:"if_need_be_count"`
+	NoCount       int                    `json:"no_count"`
+	Score         float64                `json:"score"`
+	Votes         []TimeSlotVoteResponse `json:"votes"`
 }
 
-func (h *Handler) CreateCalendarTimeSlotsEndpoint(w http.ResponseWriter, r *http.Request) {
-	calendarID := r.PathValue("calendar_id")
-	
-	var requestBody CreateCalendarTimeSlotsRequest
+type GetCalendarResponse struct {
+	ID                   string             `json:"id"`
+	Title                string             `json:"title"`
+	Description          *string            `json:"description,omitempty"`
+	Location             *string            `json:"location,omitempty"`
+	TimeZone             string             `json:"time_zone"`
+	AcceptResponsesUntil *string            `json:"accept_responses_until,omitempty"`
+	RemainingSeconds     *int64             `json:"remaining_seconds"`
+	Status               string             `json:"status"`
+	FinalTimeSlotID      *string            `json:"final_time_slot_id"`
+	IsOpen               bool               `json:"is_open"`
+	CreatedAt            string             `json:"created_at"`
+	UpdatedAt            string             `json:"updated_at"`
+	TimeSlots            []TimeSlotResponse `json:"time_slots"`
+}
 
-	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+type GetCalendarQuery struct {
+	TimeZone string `query:"tz" validate:"omitempty,timezone"`
+}
+
+func (h *Handler) GetCalendarEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	var requestQuery GetCalendarQuery
+
+	if parsingError := ParseRequest(r, RequestOptions{Query: &requestQuery}); parsingError != nil {
 		RespondError(w, http.StatusBadRequest, parsingError.Error())
 		return
 	}
 
-	calendarUUID, uuidError := utils.StringToUUID(calendarID)
+	if !h.authorizeCalendarAccess(w, r, calendarUUID) {
+		return
+	}
+
+	calendar, fetchingError := h.CalendarService.GetCalendar(r.Context(), calendarUUID)
+	if fetchingError != nil {
+		respondServiceError(w, r, fetchingError, "Failed to get calendar")
+		return
+	}
+
+	timeZone := requestQuery.TimeZone
+	if timeZone == "" {
+		timeZone = calendar.TimeZone
+	}
+
+	location, locationError := time.LoadLocation(timeZone)
+	if locationError != nil {
+		location = time.UTC
+	}
+
+	response := GetCalendarResponse{
+		ID:          utils.UUIDToString(calendar.ID),
+		Title:       calendar.Title,
+		Description: calendar.Description,
+		Location:    calendar.Location,
+		TimeZone:    location.String(),
+		Status:      string(calendar.Status),
+		IsOpen:      calendar.IsOpen,
+		CreatedAt:   calendar.CreatedAt.In(location).Format(time.RFC3339),
+		UpdatedAt:   calendar.UpdatedAt.In(location).Format(time.RFC3339),
+		TimeSlots:   make([]TimeSlotResponse, 0, len(calendar.TimeSlots)),
+	}
+
+	if calendar.AcceptResponsesUntil != nil {
+		acceptResponsesUntil := calendar.AcceptResponsesUntil.In(location).Format(time.RFC3339)
+		response.AcceptResponsesUntil = &acceptResponsesUntil
+	}
+
+	if calendar.ResponsesRemaining != nil {
+		remainingSeconds := int64(calendar.ResponsesRemaining.Seconds())
+		response.RemainingSeconds = &remainingSeconds
+	}
+
+	if calendar.FinalTimeSlotID != nil {
+		finalTimeSlotID := utils.UUIDToString(*calendar.FinalTimeSlotID)
+		response.FinalTimeSlotID = &finalTimeSlotID
+	}
+
+	for _, slot := range calendar.TimeSlots {
+		slotResponse := TimeSlotResponse{
+			ID:            utils.UUIDToString(slot.ID),
+			StartDate:     slot.StartDate.In(location).Format(time.RFC3339),
+			EndDate:       slot.EndDate.In(location).Format(time.RFC3339),
+			VoteCount:     slot.YesCount + slot.IfNeedBeCount,
+			Voters:        []string{},
+			YesCount:      slot.YesCount,
+			IfNeedBeCount: slot.IfNeedBeCount,
+			NoCount:       slot.NoCount,
+			Score:         slot.Score,
+			Votes:         make([]TimeSlotVoteResponse, 0, len(slot.Votes)),
+		}
+
+		for _, vote := range slot.Votes {
+			if vote.Preference != services.PreferenceNo {
+				slotResponse.Voters = append(slotResponse.Voters, vote.Username)
+			}
+			slotResponse.Votes = append(slotResponse.Votes, TimeSlotVoteResponse{
+				ParticipantID: utils.UUIDToString(vote.ParticipantID),
+				Username:      vote.Username,
+				Preference:    string(vote.Preference),
+			})
+		}
+
+		response.TimeSlots = append(response.TimeSlots, slotResponse)
+	}
+
+	RespondJSON(w, http.StatusOK, response)
+}
+
+type UpdateCalendarRequest struct {
+	Title                *string `json:"title,omitempty" validate:"omitempty,min=3,max=256"`
+	Description          *string `json:"description,omitempty" validate:"omitempty,max=1024"`
+	Location             *string `json:"location,omitempty" validate:"omitempty,max=512"`
+	AcceptResponsesUntil *string `json:"accept_responses_until,omitempty" validate:"omitempty,rfc3339"`
+	Password             *string `json:"password,omitempty" validate:"omitempty,max=72"`
+	TimeZone             *string `json:"time_zone,omitempty" validate:"omitempty,timezone"`
+	OrganizerEmail       *string `json:"organizer_email,omitempty" validate:"omitempty,max=254,email|len=0"`
+}
+
+func (h *Handler) UpdateCalendarEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
 	if uuidError != nil {
 		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
 		return
 	}
 
-	var timeSlots []services.TimeSlotInput
-	for _, slot := range requestBody.TimeSlots {
-		startTime, _ := time.Parse(time.RFC3339, slot.StartDate)
-		endTime, _ := time.Parse(time.RFC3339, slot.EndDate)
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
 
-		if !endTime.After(startTime) {
-			RespondError(w, http.StatusBadRequest, "end_date must be after start_date")
-			return
+	var requestBody UpdateCalendarRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	if requestBody.Password != nil && *requestBody.Password != "" && len(*requestBody.Password) < 3 {
+		RespondError(w, http.StatusBadRequest, "password must be empty to remove it or at least 3 characters long")
+		return
+	}
+
+	serviceInput := services.UpdateCalendarInput{
+		CalendarID:     calendarUUID,
+		Title:          requestBody.Title,
+		Description:    requestBody.Description,
+		Location:       requestBody.Location,
+		Password:       requestBody.Password,
+		TimeZone:       requestBody.TimeZone,
+		OrganizerEmail: requestBody.OrganizerEmail,
+	}
+
+	if requestBody.AcceptResponsesUntil != nil {
+		if *requestBody.AcceptResponsesUntil == "" {
+			serviceInput.ClearAcceptResponsesUntil = true
+		} else {
+			parsedTime, timeParsingError := time.Parse(time.RFC3339, *requestBody.AcceptResponsesUntil)
+			if timeParsingError != nil {
+				RespondError(w, http.StatusBadRequest, "Invalid time format for accept_responses_until, expected RFC3339")
+				return
+			}
+			serviceInput.AcceptResponsesUntil = &parsedTime
 		}
+	}
 
-		timeSlots = append(timeSlots, services.TimeSlotInput{
-			StartDate: startTime,
-			EndDate:   endTime,
-		})
+	if updateError := h.CalendarService.UpdateCalendar(r.Context(), serviceInput); updateError != nil {
+		respondServiceError(w, r, updateError, "Failed to update calendar")
+		return
 	}
 
-	serviceInput := services.CreateCalendarTimeSlotsInput{
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) DeleteCalendarEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	if deletionError := h.CalendarService.DeleteCalendar(r.Context(), calendarUUID); deletionError != nil {
+		respondServiceError(w, r, deletionError, "Failed to delete calendar")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type UpdateCalendarStatusRequest struct {
+	Status          string  `json:"status" validate:"required,oneof=draft open closed finalized cancelled"`
+	FinalTimeSlotID *string `json:"final_time_slot_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type UpdateCalendarStatusResponse struct {
+	Status          string  `json:"status"`
+	FinalTimeSlotID *string `json:"final_time_slot_id"`
+}
+
+func (h *Handler) UpdateCalendarStatusEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	var requestBody UpdateCalendarStatusRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	serviceInput := services.TransitionCalendarStatusInput{
 		CalendarID: calendarUUID,
-		TimeSlots:  timeSlots,
+		Status:     services.CalendarStatus(requestBody.Status),
 	}
 
-	creationError := h.CalendarService.CreateCalendarTimeSlots(r.Context(), serviceInput)
-	if creationError != nil {
-		RespondError(w, http.StatusInternalServerError, "Failed to create calendar time slots")
+	response := UpdateCalendarStatusResponse{
+		Status: requestBody.Status,
+	}
+
+	if requestBody.FinalTimeSlotID != nil && serviceInput.Status == services.CalendarStatusFinalized {
+		finalTimeSlotUUID, slotUUIDError := utils.StringToUUID(*requestBody.FinalTimeSlotID)
+		if slotUUIDError != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid final time slot ID")
+			return
+		}
+		serviceInput.FinalTimeSlotID = &finalTimeSlotUUID
+		response.FinalTimeSlotID = requestBody.FinalTimeSlotID
+	}
+
+	if transitionError := h.CalendarService.TransitionCalendarStatus(r.Context(), serviceInput); transitionError != nil {
+		respondServiceError(w, r, transitionError, "Failed to update calendar status")
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	RespondJSON(w, http.StatusOK, response)
 }
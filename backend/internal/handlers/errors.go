@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"meeting-planner/backend/internal/ical"
+	"meeting-planner/backend/internal/services"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type serviceErrorResponse struct {
+	err     error
+	status  int
+	message string
+}
+
+var serviceErrorResponses = []serviceErrorResponse{
+	{services.ErrCalendarNotFound, http.StatusNotFound, "Calendar not found"},
+	{services.ErrTimeSlotNotInCalendar, http.StatusBadRequest, "One or more time slots do not belong to this calendar"},
+	{services.ErrVoteConflict, http.StatusConflict, "Votes for this username were changed concurrently, please retry"},
+	{services.ErrPasswordRequired, http.StatusUnauthorized, "This calendar is password protected"},
+	{services.ErrInvalidPassword, http.StatusUnauthorized, "Invalid calendar password"},
+	{services.ErrPasswordNotSet, http.StatusConflict, "This calendar is not password protected, no access token is needed"},
+	{services.ErrInvalidAccessToken, http.StatusUnauthorized, "Invalid or expired access token"},
+	{services.ErrAdminTokenRequired, http.StatusUnauthorized, "Admin token required"},
+	{services.ErrInvalidAdminToken, http.StatusForbidden, "Invalid admin token"},
+	{services.ErrTimeSlotNotFound, http.StatusNotFound, "Time slot not found"},
+	{services.ErrResponsesClosed, http.StatusLocked, "This calendar no longer accepts responses"},
+	{services.ErrInvalidTimeSlot, http.StatusBadRequest, ""},
+	{services.ErrInvalidTimeZone, http.StatusBadRequest, "Invalid time_zone, expected an IANA time zone name"},
+	{ical.ErrInvalidICalendar, http.StatusBadRequest, ""},
+	{services.ErrInvalidStatusTransition, http.StatusConflict, ""},
+	{services.ErrFinalTimeSlotRequired, http.StatusBadRequest, "final_time_slot_id is required to finalize a calendar"},
+	{services.ErrCalendarLocked, http.StatusConflict, "Time slots of a finalized or cancelled calendar cannot be changed"},
+	{services.ErrParticipantNotFound, http.StatusNotFound, "Participant not found"},
+	{services.ErrParticipantNameTaken, http.StatusConflict, "This name is already used in this calendar, edit that response with its edit token instead"},
+	{services.ErrEditTokenRequired, http.StatusUnauthorized, "Participant edit token required"},
+	{services.ErrInvalidEditToken, http.StatusForbidden, "Invalid participant edit token"},
+	{services.ErrWebhookNotFound, http.StatusNotFound, "Webhook not found"},
+	{services.ErrInvalidVotePreference, http.StatusBadRequest, "Invalid preference, expected yes, if_need_be or no"},
+}
+
+func respondServiceError(w http.ResponseWriter, r *http.Request, serviceError error, fallbackMessage string) {
+	for _, response := range serviceErrorResponses {
+		if !errors.Is(serviceError, response.err) {
+			continue
+		}
+
+		message := response.message
+		if message == "" {
+			message = serviceError.Error()
+		}
+		RespondError(w, response.status, message)
+		return
+	}
+
+	slog.ErrorContext(r.Context(), fallbackMessage, "error", serviceError)
+	trace.SpanFromContext(r.Context()).RecordError(serviceError)
+	RespondError(w, http.StatusInternalServerError, fallbackMessage)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"time"
+)
+
+const (
+	eventStreamHeartbeatInterval = 25 * time.Second
+	eventStreamRetryMilliseconds = 5000
+)
+
+type CalendarEventPayload struct {
+	Type       string         `json:"type"`
+	CalendarID string         `json:"calendar_id"`
+	OccurredAt string         `json:"occurred_at"`
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+func (h *Handler) CalendarEventsEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAccess(w, r, calendarUUID) {
+		return
+	}
+
+	subscription, subscriptionError := h.Events.Subscribe(calendarUUID)
+	if subscriptionError != nil {
+		RespondError(w, http.StatusServiceUnavailable, "Server is shutting down")
+		return
+	}
+	defer subscription.Close()
+
+	// The server-wide read and write timeouts would cut the stream after a
+	// few seconds, so they are lifted for this connection only.
+	responseController := http.NewResponseController(w)
+	if deadlineError := responseController.SetWriteDeadline(time.Time{}); deadlineError != nil && !errors.Is(deadlineError, http.ErrNotSupported) {
+		RespondError(w, http.StatusInternalServerError, "Failed to open event stream")
+		return
+	}
+	_ = responseController.SetReadDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetryMilliseconds)
+	if flushError := responseController.Flush(); flushError != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, writeError := fmt.Fprint(w, ": heartbeat\n\n"); writeError != nil {
+				return
+			}
+		case event, open := <-subscription.Events:
+			if !open {
+				return
+			}
+			if writeError := writeServerSentEvent(w, event); writeError != nil {
+				return
+			}
+			if event.Type == events.TypeCalendarDeleted {
+				_ = responseController.Flush()
+				return
+			}
+		}
+
+		if flushError := responseController.Flush(); flushError != nil {
+			return
+		}
+	}
+}
+
+func writeServerSentEvent(w http.ResponseWriter, event events.Event) error {
+	payload, encodingError := json.Marshal(CalendarEventPayload{
+		Type:       event.Type,
+		CalendarID: utils.UUIDToString(event.CalendarID),
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339),
+		Data:       event.Data,
+	})
+	if encodingError != nil {
+		return encodingError
+	}
+
+	_, writeError := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+	return writeError
+}
@@ -2,17 +2,21 @@ package handlers
 
 import (
 	"meeting-planner/backend/internal/db"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/notifications"
 	"meeting-planner/backend/internal/services"
 )
 
 type Handler struct {
 	DB              *db.DB
 	CalendarService *services.CalendarService
+	Events          *events.Hub
 }
 
-func New(database *db.DB) *Handler {
+func New(database *db.DB, accessTokenSecret []byte, eventHub *events.Hub, notifier *notifications.Notifier) *Handler {
 	return &Handler{
 		DB:              database,
-		CalendarService: services.NewCalendarService(database.Queries),
+		CalendarService: services.NewCalendarService(database, accessTokenSecret, eventHub, notifier),
+		Events:          eventHub,
 	}
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 )
 
@@ -35,7 +36,7 @@ func (h *Handler) EchoEndpoint(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if parsingError != nil {
-		fmt.Printf("Error parsing request: %v\n", parsingError)
+		slog.DebugContext(r.Context(), "failed to parse echo request", "error", parsingError)
 		RespondError(w, http.StatusBadRequest, parsingError.Error())
 		return
 	}
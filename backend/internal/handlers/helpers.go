@@ -20,8 +20,16 @@ func RespondJSON(w http.ResponseWriter, status int, data any) {
 	}
 }
 
+const requestIDHeader = "X-Request-ID"
+
+// RespondError includes the request ID set by middleware.RequestID so that a
+// reported error can be matched to its log lines.
 func RespondError(w http.ResponseWriter, status int, message string) {
-	RespondJSON(w, status, map[string]any{"error": message})
+	responseBody := map[string]any{"error": message}
+	if requestID := w.Header().Get(requestIDHeader); requestID != "" {
+		responseBody["request_id"] = requestID
+	}
+	RespondJSON(w, status, responseBody)
 }
 
 func ToJSON(value any) string {
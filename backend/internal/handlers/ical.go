@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"meeting-planner/backend/internal/ical"
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"time"
+)
+
+const icalProductID = "-//meeting-planner//hangout-planner//EN"
+
+func (h *Handler) ExportCalendarICSEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarFeed(w, r, calendarUUID) {
+		return
+	}
+
+	calendar, fetchingError := h.CalendarService.GetCalendar(r.Context(), calendarUUID)
+	if fetchingError != nil {
+		respondServiceError(w, r, fetchingError, "Failed to get calendar")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_ = ical.Encode(w, buildICalendar(calendar))
+}
+
+func buildICalendar(calendar services.CalendarDetails) ical.Calendar {
+	var description, location string
+	if calendar.Description != nil {
+		description = *calendar.Description
+	}
+	if calendar.Location != nil {
+		location = *calendar.Location
+	}
+
+	eventStatus := ical.StatusTentative
+	if calendar.Status == services.CalendarStatusCancelled {
+		eventStatus = ical.StatusCancelled
+	}
+
+	// Every change to the calendar or its time slots bumps UpdatedAt, so
+	// the seconds since creation grow with each revision as SEQUENCE must.
+	sequence := int(calendar.UpdatedAt.Sub(calendar.CreatedAt) / time.Second)
+
+	events := make([]ical.Event, 0, len(calendar.TimeSlots))
+	for _, slot := range calendar.TimeSlots {
+		event := ical.Event{
+			UID:          utils.UUIDToString(slot.ID) + "@meeting-planner",
+			Sequence:     sequence,
+			Summary:      calendar.Title,
+			Description:  description,
+			Location:     location,
+			Status:       eventStatus,
+			Start:        slot.StartDate,
+			End:          slot.EndDate,
+			Stamp:        calendar.UpdatedAt,
+			LastModified: calendar.UpdatedAt,
+		}
+
+		if calendar.Status == services.CalendarStatusFinalized {
+			if calendar.FinalTimeSlotID == nil || *calendar.FinalTimeSlotID != slot.ID {
+				continue
+			}
+			event.UID = utils.UUIDToString(calendar.ID) + "@meeting-planner"
+			event.Status = ical.StatusConfirmed
+		}
+
+		events = append(events, event)
+	}
+
+	return ical.Calendar{
+		ProductID: icalProductID,
+		Name:      calendar.Title,
+		Events:    events,
+	}
+}
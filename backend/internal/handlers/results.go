@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"time"
+)
+
+type GetCalendarResultsQuery struct {
+	Quorum   int    `query:"quorum" validate:"min=0,max=10000"`
+	TimeZone string `query:"tz" validate:"omitempty,timezone"`
+}
+
+type RankedTimeSlotResponse struct {
+	ID                  string   `json:"id"`
+	StartDate           string   `json:"start_date"`
+	EndDate             string   `json:"end_date"`
+	Rank                int      `json:"rank"`
+	Score               float64  `json:"score"`
+	YesCount            int      `json:"yes_count"`
+	IfNeedBeCount       int      `json:"if_need_be_count"`
+	NoCount             int      `json:"no_count"`
+	MissingParticipants []string `json:"missing_participants"`
+	MeetsQuorum         bool     `json:"meets_quorum"`
+}
+
+type GetCalendarResultsResponse struct {
+	Quorum         int                      `json:"quorum"`
+	Participants   []string                 `json:"participants"`
+	BestTimeSlotID *string                  `json:"best_time_slot_id"`
+	TimeSlots      []RankedTimeSlotResponse `json:"time_slots"`
+}
+
+func (h *Handler) GetCalendarResultsEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	var requestQuery GetCalendarResultsQuery
+
+	if parsingError := ParseRequest(r, RequestOptions{Query: &requestQuery}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	if !h.authorizeCalendarAccess(w, r, calendarUUID) {
+		return
+	}
+
+	timeZone := requestQuery.TimeZone
+	if timeZone == "" {
+		calendarTimeZone, timeZoneError := h.CalendarService.GetCalendarTimeZone(r.Context(), calendarUUID)
+		if timeZoneError != nil {
+			respondServiceError(w, r, timeZoneError, "Failed to get calendar")
+			return
+		}
+		timeZone = calendarTimeZone
+	}
+
+	location, locationError := time.LoadLocation(timeZone)
+	if locationError != nil {
+		location = time.UTC
+	}
+
+	results, resultsError := h.CalendarService.GetCalendarResults(r.Context(), calendarUUID, services.RankingOptions{
+		Quorum: requestQuery.Quorum,
+	})
+	if resultsError != nil {
+		respondServiceError(w, r, resultsError, "Failed to get calendar results")
+		return
+	}
+
+	response := GetCalendarResultsResponse{
+		Quorum:       requestQuery.Quorum,
+		Participants: results.Participants,
+		TimeSlots:    make([]RankedTimeSlotResponse, 0, len(results.TimeSlots)),
+	}
+
+	if results.BestTimeSlotID != nil {
+		bestTimeSlotID := utils.UUIDToString(*results.BestTimeSlotID)
+		response.BestTimeSlotID = &bestTimeSlotID
+	}
+
+	for _, slot := range results.TimeSlots {
+		response.TimeSlots = append(response.TimeSlots, RankedTimeSlotResponse{
+			ID:                  utils.UUIDToString(slot.TimeSlotID),
+			StartDate:           slot.StartDate.In(location).Format(time.RFC3339),
+			EndDate:             slot.EndDate.In(location).Format(time.RFC3339),
+			Rank:                slot.Rank,
+			Score:               slot.Score,
+			YesCount:            slot.YesCount,
+			IfNeedBeCount:       slot.IfNeedBeCount,
+			NoCount:             slot.NoCount,
+			MissingParticipants: slot.MissingParticipants,
+			MeetsQuorum:         slot.MeetsQuorum,
+		})
+	}
+
+	RespondJSON(w, http.StatusOK, response)
+}
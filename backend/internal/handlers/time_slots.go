@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CalendarTimeSlots struct {
+	StartDate string `json:"start_date" validate:"required"`
+	EndDate   string `json:"end_date" validate:"required"`
+	TimeZone  string `json:"time_zone,omitempty" validate:"omitempty,timezone"`
+}
+
+var wallClockLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+func parseSlotTime(value string) (time.Time, bool, error) {
+	if parsedTime, parsingError := time.Parse(time.RFC3339, value); parsingError == nil {
+		return parsedTime, false, nil
+	}
+
+	for _, layout := range wallClockLayouts {
+		if parsedTime, parsingError := time.Parse(layout, value); parsingError == nil {
+			return parsedTime, true, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("expected RFC3339 or local date time")
+}
+
+func parseTimeSlot(slot CalendarTimeSlots) (services.TimeSlotInput, error) {
+	startTime, startIsWallClock, startParsingError := parseSlotTime(slot.StartDate)
+	if startParsingError != nil {
+		return services.TimeSlotInput{}, fmt.Errorf("Invalid time format for start_date, %w", startParsingError)
+	}
+
+	endTime, endIsWallClock, endParsingError := parseSlotTime(slot.EndDate)
+	if endParsingError != nil {
+		return services.TimeSlotInput{}, fmt.Errorf("Invalid time format for end_date, %w", endParsingError)
+	}
+
+	if startIsWallClock != endIsWallClock {
+		return services.TimeSlotInput{}, fmt.Errorf("start_date and end_date must both be RFC3339 or both be local date times")
+	}
+
+	if !startIsWallClock && slot.TimeZone != "" {
+		return services.TimeSlotInput{}, fmt.Errorf("time_zone can only be used with local date times")
+	}
+
+	return services.TimeSlotInput{
+		StartDate:   startTime,
+		EndDate:     endTime,
+		IsWallClock: startIsWallClock,
+		TimeZone:    slot.TimeZone,
+	}, nil
+}
+
+type CreateCalendarTimeSlotsRequest struct {
+	TimeSlots []CalendarTimeSlots `json:"time_slots" validate:"required,dive,required"`
+}
+
+type CreateCalendarTimeSlotsResponse struct {
+	TimeSlotIDs []string `json:"time_slot_ids"`
+}
+
+func uuidsToStrings(uuids []pgtype.UUID) []string {
+	uuidStrings := make([]string, 0, len(uuids))
+	for _, value := range uuids {
+		uuidStrings = append(uuidStrings, utils.UUIDToString(value))
+	}
+	return uuidStrings
+}
+
+func (h *Handler) CreateCalendarTimeSlotsEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	var requestBody CreateCalendarTimeSlotsRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	var timeSlots []services.TimeSlotInput
+	for _, slot := range requestBody.TimeSlots {
+		timeSlot, slotError := parseTimeSlot(slot)
+		if slotError != nil {
+			RespondError(w, http.StatusBadRequest, slotError.Error())
+			return
+		}
+
+		timeSlots = append(timeSlots, timeSlot)
+	}
+
+	serviceInput := services.CreateCalendarTimeSlotsInput{
+		CalendarID: calendarUUID,
+		TimeSlots:  timeSlots,
+	}
+
+	timeSlotIDs, creationError := h.CalendarService.CreateCalendarTimeSlots(r.Context(), serviceInput)
+	if creationError != nil {
+		respondServiceError(w, r, creationError, "Failed to create calendar time slots")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, CreateCalendarTimeSlotsResponse{
+		TimeSlotIDs: uuidsToStrings(timeSlotIDs),
+	})
+}
+
+func (h *Handler) UpdateCalendarTimeSlotEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	timeSlotUUID, slotUUIDError := utils.StringToUUID(r.PathValue("time_slot_id"))
+	if slotUUIDError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid time slot ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	var requestBody CalendarTimeSlots
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	timeSlot, slotError := parseTimeSlot(requestBody)
+	if slotError != nil {
+		RespondError(w, http.StatusBadRequest, slotError.Error())
+		return
+	}
+
+	serviceInput := services.UpdateCalendarTimeSlotInput{
+		CalendarID: calendarUUID,
+		TimeSlotID: timeSlotUUID,
+		TimeSlot:   timeSlot,
+	}
+
+	if updateError := h.CalendarService.UpdateCalendarTimeSlot(r.Context(), serviceInput); updateError != nil {
+		respondServiceError(w, r, updateError, "Failed to update calendar time slot")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) DeleteCalendarTimeSlotEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	timeSlotUUID, slotUUIDError := utils.StringToUUID(r.PathValue("time_slot_id"))
+	if slotUUIDError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid time slot ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	if deletionError := h.CalendarService.DeleteCalendarTimeSlot(r.Context(), calendarUUID, timeSlotUUID); deletionError != nil {
+		respondServiceError(w, r, deletionError, "Failed to delete calendar time slot")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type GenerateCalendarTimeSlotsRequest struct {
+	StartDate      string  `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate        string  `json:"end_date" validate:"required,datetime=2006-01-02"`
+	DailyStartTime string  `json:"daily_start_time" validate:"required_unless=IsWholeDay true,omitempty,datetime=15:04"`
+	DailyEndTime   string  `json:"daily_end_time" validate:"required_unless=IsWholeDay true,omitempty,datetime=15:04"`
+	DurationHours  float64 `json:"duration_hours" validate:"required_unless=IsWholeDay true,omitempty,gt=0,lte=24"`
+	IsOverlapping  bool    `json:"is_overlapping"`
+	IsWholeDay     bool    `json:"is_whole_day"`
+	TimeZone       string  `json:"time_zone" validate:"omitempty,timezone"`
+	Persist        bool    `json:"persist"`
+}
+
+type GeneratedTimeSlotResponse struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type GenerateCalendarTimeSlotsResponse struct {
+	TimeSlots   []GeneratedTimeSlotResponse `json:"time_slots"`
+	TimeSlotIDs []string                    `json:"time_slot_ids,omitempty"`
+}
+
+func parseClockTime(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	clockTime, _ := time.Parse("15:04", value)
+	return time.Duration(clockTime.Hour())*time.Hour + time.Duration(clockTime.Minute())*time.Minute
+}
+
+func (h *Handler) GenerateCalendarTimeSlotsEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	var requestBody GenerateCalendarTimeSlotsRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	startDate, _ := time.Parse(time.DateOnly, requestBody.StartDate)
+	endDate, _ := time.Parse(time.DateOnly, requestBody.EndDate)
+
+	timeZone := requestBody.TimeZone
+	if timeZone == "" {
+		calendarTimeZone, timeZoneError := h.CalendarService.GetCalendarTimeZone(r.Context(), calendarUUID)
+		if timeZoneError != nil {
+			respondServiceError(w, r, timeZoneError, "Failed to get calendar time zone")
+			return
+		}
+		timeZone = calendarTimeZone
+	}
+
+	location, locationError := time.LoadLocation(timeZone)
+	if locationError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid time_zone, expected an IANA time zone name")
+		return
+	}
+
+	timeSlots, generationError := services.GenerateTimeSlots(services.GenerateTimeSlotsInput{
+		StartDate:      startDate,
+		EndDate:        endDate,
+		DailyStartTime: parseClockTime(requestBody.DailyStartTime),
+		DailyEndTime:   parseClockTime(requestBody.DailyEndTime),
+		Duration:       time.Duration(math.Round(requestBody.DurationHours*3600)) * time.Second,
+		IsOverlapping:  requestBody.IsOverlapping,
+		IsWholeDay:     requestBody.IsWholeDay,
+		Location:       location,
+	})
+	if generationError != nil {
+		RespondError(w, http.StatusBadRequest, generationError.Error())
+		return
+	}
+
+	response := GenerateCalendarTimeSlotsResponse{
+		TimeSlots: make([]GeneratedTimeSlotResponse, 0, len(timeSlots)),
+	}
+	for _, slot := range timeSlots {
+		response.TimeSlots = append(response.TimeSlots, GeneratedTimeSlotResponse{
+			StartDate: slot.StartDate.Format(time.RFC3339),
+			EndDate:   slot.EndDate.Format(time.RFC3339),
+		})
+	}
+
+	if !requestBody.Persist {
+		RespondJSON(w, http.StatusOK, response)
+		return
+	}
+
+	timeSlotIDs, creationError := h.CalendarService.CreateCalendarTimeSlots(r.Context(), services.CreateCalendarTimeSlotsInput{
+		CalendarID: calendarUUID,
+		TimeSlots:  timeSlots,
+	})
+	if creationError != nil {
+		respondServiceError(w, r, creationError, "Failed to create calendar time slots")
+		return
+	}
+
+	response.TimeSlotIDs = uuidsToStrings(timeSlotIDs)
+
+	RespondJSON(w, http.StatusCreated, response)
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const participantTokenHeader = "X-Participant-Token"
+
+type VoteRequest struct {
+	TimeSlotID string `json:"time_slot_id" validate:"required,uuid"`
+	Preference string `json:"preference" validate:"required,oneof=yes if_need_be no"`
+}
+
+type CreateVotesRequest struct {
+	Username    string        `json:"username" validate:"required,min=1,max=128"`
+	TimeSlotIDs []string      `json:"time_slot_ids,omitempty" validate:"required_without=Votes,omitempty,unique,dive,uuid"`
+	Votes       []VoteRequest `json:"votes,omitempty" validate:"required_without=TimeSlotIDs,omitempty,unique=TimeSlotID,dive"`
+}
+
+type VoteResponse struct {
+	TimeSlotID string `json:"time_slot_id"`
+	Preference string `json:"preference"`
+}
+
+type CreateVotesResponse struct {
+	ParticipantID string         `json:"participant_id"`
+	EditToken     string         `json:"edit_token"`
+	Username      string         `json:"username"`
+	Votes         []VoteResponse `json:"votes"`
+}
+
+func (h *Handler) CreateVotesEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAccess(w, r, calendarUUID) {
+		return
+	}
+
+	var requestBody CreateVotesRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	username := strings.TrimSpace(requestBody.Username)
+	if username == "" {
+		RespondError(w, http.StatusBadRequest, "username must not be blank")
+		return
+	}
+
+	requestedVotes := combineVoteRequests(requestBody.TimeSlotIDs, requestBody.Votes)
+
+	votes, votesError := parseVoteRequests(requestedVotes)
+	if votesError != nil {
+		RespondError(w, http.StatusBadRequest, votesError.Error())
+		return
+	}
+
+	submittedResponse, submissionError := h.CalendarService.SubmitResponse(r.Context(), services.SubmitResponseInput{
+		CalendarID:  calendarUUID,
+		DisplayName: username,
+		Votes:       votes,
+	})
+	if submissionError != nil {
+		respondServiceError(w, r, submissionError, "Failed to save votes")
+		return
+	}
+
+	response := CreateVotesResponse{
+		ParticipantID: utils.UUIDToString(submittedResponse.ParticipantID),
+		EditToken:     submittedResponse.EditToken,
+		Username:      username,
+		Votes:         make([]VoteResponse, 0, len(requestedVotes)),
+	}
+	for _, vote := range requestedVotes {
+		response.Votes = append(response.Votes, VoteResponse(vote))
+	}
+
+	RespondJSON(w, http.StatusCreated, response)
+}
+
+type UpdateParticipantRequest struct {
+	Username    *string       `json:"username,omitempty" validate:"omitempty,min=1,max=128"`
+	TimeSlotIDs []string      `json:"time_slot_ids,omitempty" validate:"omitempty,unique,dive,uuid"`
+	Votes       []VoteRequest `json:"votes,omitempty" validate:"omitempty,unique=TimeSlotID,dive"`
+}
+
+func (h *Handler) UpdateParticipantEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	participantUUID, participantUUIDError := utils.StringToUUID(r.PathValue("participant_id"))
+	if participantUUIDError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid participant ID")
+		return
+	}
+
+	if !h.authorizeParticipant(w, r, calendarUUID, participantUUID) {
+		return
+	}
+
+	var requestBody UpdateParticipantRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	serviceInput := services.UpdateResponseInput{
+		CalendarID:    calendarUUID,
+		ParticipantID: participantUUID,
+	}
+
+	if requestBody.Username != nil {
+		username := strings.TrimSpace(*requestBody.Username)
+		if username == "" {
+			RespondError(w, http.StatusBadRequest, "username must not be blank")
+			return
+		}
+		serviceInput.DisplayName = &username
+	}
+
+	if requestBody.TimeSlotIDs != nil || requestBody.Votes != nil {
+		votes, votesError := parseVoteRequests(combineVoteRequests(requestBody.TimeSlotIDs, requestBody.Votes))
+		if votesError != nil {
+			RespondError(w, http.StatusBadRequest, votesError.Error())
+			return
+		}
+		serviceInput.Votes = votes
+	}
+
+	if updateError := h.CalendarService.UpdateResponse(r.Context(), serviceInput); updateError != nil {
+		respondServiceError(w, r, updateError, "Failed to update response")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) DeleteParticipantEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	participantUUID, participantUUIDError := utils.StringToUUID(r.PathValue("participant_id"))
+	if participantUUIDError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid participant ID")
+		return
+	}
+
+	if !h.authorizeParticipant(w, r, calendarUUID, participantUUID) {
+		return
+	}
+
+	if withdrawalError := h.CalendarService.WithdrawResponse(r.Context(), calendarUUID, participantUUID); withdrawalError != nil {
+		respondServiceError(w, r, withdrawalError, "Failed to withdraw response")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeParticipant accepts either the participant's own edit token or the
+// calendar's admin token, so organizers can still remove unwanted responses.
+func (h *Handler) authorizeParticipant(w http.ResponseWriter, r *http.Request, calendarID pgtype.UUID, participantID pgtype.UUID) bool {
+	if r.Header.Get(adminTokenHeader) != "" {
+		return h.authorizeCalendarAdmin(w, r, calendarID)
+	}
+
+	authorizationError := h.CalendarService.AuthorizeParticipant(r.Context(), calendarID, participantID, r.Header.Get(participantTokenHeader))
+	if authorizationError != nil {
+		respondServiceError(w, r, authorizationError, "Failed to authorize participant")
+		return false
+	}
+
+	return true
+}
+
+func combineVoteRequests(timeSlotIDs []string, votes []VoteRequest) []VoteRequest {
+	requestedVotes := make([]VoteRequest, 0, len(timeSlotIDs)+len(votes))
+	for _, slotID := range timeSlotIDs {
+		requestedVotes = append(requestedVotes, VoteRequest{
+			TimeSlotID: slotID,
+			Preference: string(services.PreferenceYes),
+		})
+	}
+	return append(requestedVotes, votes...)
+}
+
+func parseVoteRequests(requestedVotes []VoteRequest) ([]services.VoteInput, error) {
+	votes := make([]services.VoteInput, 0, len(requestedVotes))
+	votedSlotIDs := make(map[pgtype.UUID]struct{}, len(requestedVotes))
+
+	for _, vote := range requestedVotes {
+		slotUUID, slotUUIDError := utils.StringToUUID(vote.TimeSlotID)
+		if slotUUIDError != nil {
+			return nil, errors.New("Invalid time slot ID")
+		}
+		if _, alreadyVoted := votedSlotIDs[slotUUID]; alreadyVoted {
+			return nil, errors.New("Each time slot can only be voted on once")
+		}
+		votedSlotIDs[slotUUID] = struct{}{}
+
+		votes = append(votes, services.VoteInput{
+			TimeSlotID: slotUUID,
+			Preference: services.VotePreference(vote.Preference),
+		})
+	}
+
+	return votes, nil
+}
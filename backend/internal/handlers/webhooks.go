@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/services"
+	"meeting-planner/backend/internal/utils"
+	"meeting-planner/backend/internal/webhooks"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes []string `json:"event_types,omitempty" validate:"omitempty,unique,dive,webhook_event_type"`
+}
+
+func init() {
+	_ = validate.RegisterValidation("webhook_event_type", func(fieldLevel validator.FieldLevel) bool {
+		return fieldLevel.Field().Kind() == reflect.String && slices.Contains(webhooks.EventTypes, fieldLevel.Field().String())
+	})
+}
+
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID         string  `json:"id"`
+	DeliveryID string  `json:"delivery_id"`
+	EventType  string  `json:"event_type"`
+	Attempt    int32   `json:"attempt"`
+	StatusCode *int32  `json:"status_code"`
+	Error      *string `json:"error"`
+	Succeeded  bool    `json:"succeeded"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+func (h *Handler) CreateWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	var requestBody CreateWebhookRequest
+
+	if parsingError := ParseRequest(r, RequestOptions{Body: &requestBody}); parsingError != nil {
+		RespondError(w, http.StatusBadRequest, parsingError.Error())
+		return
+	}
+
+	webhookURL, urlError := url.Parse(requestBody.URL)
+	if urlError != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		RespondError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+
+	createdWebhook, creationError := h.CalendarService.CreateWebhook(r.Context(), services.CreateWebhookInput{
+		CalendarID: calendarUUID,
+		URL:        requestBody.URL,
+		EventTypes: requestBody.EventTypes,
+	})
+	if creationError != nil {
+		respondServiceError(w, r, creationError, "Failed to create webhook")
+		return
+	}
+
+	eventTypes := requestBody.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	RespondJSON(w, http.StatusCreated, CreateWebhookResponse{
+		WebhookResponse: WebhookResponse{
+			ID:         utils.UUIDToString(createdWebhook.ID),
+			URL:        requestBody.URL,
+			EventTypes: eventTypes,
+			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		},
+		Secret: createdWebhook.Secret,
+	})
+}
+
+func (h *Handler) ListWebhooksEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	webhooks, listingError := h.CalendarService.ListWebhooks(r.Context(), calendarUUID)
+	if listingError != nil {
+		respondServiceError(w, r, listingError, "Failed to list webhooks")
+		return
+	}
+
+	response := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		response = append(response, toWebhookResponse(webhook))
+	}
+
+	RespondJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) DeleteWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	webhookUUID, webhookUUIDError := utils.StringToUUID(r.PathValue("webhook_id"))
+	if webhookUUIDError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	if deletionError := h.CalendarService.DeleteWebhook(r.Context(), calendarUUID, webhookUUID); deletionError != nil {
+		respondServiceError(w, r, deletionError, "Failed to delete webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListWebhookDeliveriesEndpoint(w http.ResponseWriter, r *http.Request) {
+	calendarUUID, uuidError := utils.StringToUUID(r.PathValue("calendar_id"))
+	if uuidError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid calendar ID")
+		return
+	}
+
+	webhookUUID, webhookUUIDError := utils.StringToUUID(r.PathValue("webhook_id"))
+	if webhookUUIDError != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if !h.authorizeCalendarAdmin(w, r, calendarUUID) {
+		return
+	}
+
+	deliveries, listingError := h.CalendarService.ListWebhookDeliveries(r.Context(), calendarUUID, webhookUUID)
+	if listingError != nil {
+		respondServiceError(w, r, listingError, "Failed to list webhook deliveries")
+		return
+	}
+
+	response := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, WebhookDeliveryResponse{
+			ID:         utils.UUIDToString(delivery.ID),
+			DeliveryID: utils.UUIDToString(delivery.DeliveryID),
+			EventType:  delivery.EventType,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Error:      delivery.Error,
+			Succeeded:  delivery.Succeeded,
+			CreatedAt:  delivery.CreatedAt.Time.UTC().Format(time.RFC3339),
+		})
+	}
+
+	RespondJSON(w, http.StatusOK, response)
+}
+
+func toWebhookResponse(webhook sqlc.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:         utils.UUIDToString(webhook.ID),
+		URL:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		CreatedAt:  webhook.CreatedAt.Time.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,126 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+
+	utcLayout     = "20060102T150405Z"
+	maxLineOctets = 75
+)
+
+type Event struct {
+	UID          string
+	Sequence     int
+	Summary      string
+	Description  string
+	Location     string
+	Status       string
+	Start        time.Time
+	End          time.Time
+	Stamp        time.Time
+	LastModified time.Time
+}
+
+type Calendar struct {
+	ProductID string
+	Name      string
+	Events    []Event
+}
+
+func Encode(writer io.Writer, calendar Calendar) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	lineWriter := &contentLineWriter{writer: bufferedWriter}
+
+	lineWriter.write("BEGIN", "VCALENDAR")
+	lineWriter.write("VERSION", "2.0")
+	lineWriter.write("PRODID", calendar.ProductID)
+	lineWriter.write("CALSCALE", "GREGORIAN")
+	lineWriter.write("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		lineWriter.write("X-WR-CALNAME", escapeText(calendar.Name))
+	}
+
+	for _, event := range calendar.Events {
+		lineWriter.write("BEGIN", "VEVENT")
+		lineWriter.write("UID", event.UID)
+		lineWriter.write("SEQUENCE", fmt.Sprint(event.Sequence))
+		lineWriter.write("DTSTAMP", formatUTC(event.Stamp))
+		lineWriter.write("DTSTART", formatUTC(event.Start))
+		lineWriter.write("DTEND", formatUTC(event.End))
+		lineWriter.write("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			lineWriter.write("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			lineWriter.write("LOCATION", escapeText(event.Location))
+		}
+		if event.Status != "" {
+			lineWriter.write("STATUS", event.Status)
+		}
+		if !event.LastModified.IsZero() {
+			lineWriter.write("LAST-MODIFIED", formatUTC(event.LastModified))
+		}
+		lineWriter.write("END", "VEVENT")
+	}
+
+	lineWriter.write("END", "VCALENDAR")
+
+	if lineWriter.err != nil {
+		return lineWriter.err
+	}
+	return bufferedWriter.Flush()
+}
+
+func formatUTC(value time.Time) string {
+	return value.UTC().Format(utcLayout)
+}
+
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+type contentLineWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (w *contentLineWriter) write(name string, value string) {
+	if w.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+	var folded strings.Builder
+	lineOctets := 0
+
+	for _, character := range line {
+		characterOctets := utf8.RuneLen(character)
+		if lineOctets+characterOctets > maxLineOctets {
+			folded.WriteString("\r\n ")
+			lineOctets = 1
+		}
+		folded.WriteRune(character)
+		lineOctets += characterOctets
+	}
+	folded.WriteString("\r\n")
+
+	_, w.err = io.WriteString(w.writer, folded.String())
+}
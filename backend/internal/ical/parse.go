@@ -0,0 +1,503 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localLayout = "20060102T150405"
+	dateLayout  = "20060102"
+)
+
+var ErrInvalidICalendar = errors.New("invalid iCalendar data")
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Overlaps(start time.Time, end time.Time) bool {
+	return i.Start.Before(end) && start.Before(i.End)
+}
+
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// ParseBusyIntervals reads VEVENT and VFREEBUSY components and returns the
+// intervals they mark as busy. Floating times, dates and TZIDs that are not
+// IANA names are placed in defaultLocation. Recurring events are expanded
+// into the occurrences that overlap window; recurrence rules this package
+// cannot expand are rejected rather than reported as a single occurrence.
+func ParseBusyIntervals(reader io.Reader, defaultLocation *time.Location, window Interval) ([]Interval, error) {
+	lines, readingError := readContentLines(reader)
+	if readingError != nil {
+		return nil, readingError
+	}
+
+	intervals := []Interval{}
+	var events []event
+	var componentStack []string
+	var eventLines []contentLine
+	foundCalendar := false
+
+	for _, line := range lines {
+		switch line.name {
+		case "BEGIN":
+			component := strings.ToUpper(line.value)
+			componentStack = append(componentStack, component)
+			if component == "VCALENDAR" {
+				foundCalendar = true
+			}
+			if component == "VEVENT" {
+				eventLines = nil
+			}
+			continue
+		case "END":
+			component := strings.ToUpper(line.value)
+			if len(componentStack) == 0 || componentStack[len(componentStack)-1] != component {
+				return nil, fmt.Errorf("%w: unexpected END:%s", ErrInvalidICalendar, line.value)
+			}
+			componentStack = componentStack[:len(componentStack)-1]
+
+			if component == "VEVENT" {
+				parsedEvent, eventError := parseEvent(eventLines, defaultLocation)
+				if eventError != nil {
+					return nil, eventError
+				}
+				events = append(events, parsedEvent)
+			}
+			continue
+		}
+
+		if len(componentStack) == 0 {
+			continue
+		}
+
+		switch componentStack[len(componentStack)-1] {
+		case "VEVENT":
+			eventLines = append(eventLines, line)
+		case "VFREEBUSY":
+			if line.name != "FREEBUSY" {
+				continue
+			}
+			periods, periodsError := freeBusyIntervals(line)
+			if periodsError != nil {
+				return nil, periodsError
+			}
+			intervals = append(intervals, periods...)
+		}
+	}
+
+	if !foundCalendar {
+		return nil, fmt.Errorf("%w: missing VCALENDAR", ErrInvalidICalendar)
+	}
+	if len(componentStack) != 0 {
+		return nil, fmt.Errorf("%w: unterminated %s", ErrInvalidICalendar, componentStack[len(componentStack)-1])
+	}
+
+	eventIntervals, expansionError := busyEventIntervals(events, window)
+	if expansionError != nil {
+		return nil, expansionError
+	}
+
+	return append(intervals, eventIntervals...), nil
+}
+
+func readContentLines(reader io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var unfolded []string
+	for scanner.Scan() {
+		rawLine := strings.TrimRight(scanner.Text(), "\r")
+		if rawLine == "" {
+			continue
+		}
+		if (rawLine[0] == ' ' || rawLine[0] == '\t') && len(unfolded) > 0 {
+			unfolded[len(unfolded)-1] += rawLine[1:]
+			continue
+		}
+		unfolded = append(unfolded, rawLine)
+	}
+	if scanError := scanner.Err(); scanError != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidICalendar, scanError)
+	}
+
+	lines := make([]contentLine, 0, len(unfolded))
+	for _, rawLine := range unfolded {
+		line, lineError := parseContentLine(rawLine)
+		if lineError != nil {
+			return nil, lineError
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func parseContentLine(rawLine string) (contentLine, error) {
+	inQuotes := false
+	separatorIndex := -1
+	for index, character := range rawLine {
+		if character == '"' {
+			inQuotes = !inQuotes
+		}
+		if character == ':' && !inQuotes {
+			separatorIndex = index
+			break
+		}
+	}
+	if separatorIndex < 0 {
+		return contentLine{}, fmt.Errorf("%w: malformed line %q", ErrInvalidICalendar, rawLine)
+	}
+
+	nameAndParams := strings.Split(rawLine[:separatorIndex], ";")
+	line := contentLine{
+		name:   strings.ToUpper(nameAndParams[0]),
+		params: make(map[string]string, len(nameAndParams)-1),
+		value:  rawLine[separatorIndex+1:],
+	}
+
+	for _, param := range nameAndParams[1:] {
+		paramName, paramValue, _ := strings.Cut(param, "=")
+		line.params[strings.ToUpper(paramName)] = strings.Trim(paramValue, `"`)
+	}
+
+	return line, nil
+}
+
+type event struct {
+	uid   string
+	start time.Time
+	// Occurrences of an event with a DATE start last whole days, which are
+	// not always 24 hours long, so days is used instead of duration.
+	isDate   bool
+	days     int
+	duration time.Duration
+	isFree   bool
+	// recurrenceID is set on an event that replaces one occurrence of the
+	// recurring event with the same UID.
+	recurrenceID  time.Time
+	rules         []recurrenceRule
+	extraStarts   []time.Time
+	excludedDates []dateTimeValue
+}
+
+func (e event) endOf(start time.Time) time.Time {
+	if e.days > 0 {
+		return start.AddDate(0, 0, e.days)
+	}
+	return start.Add(e.duration)
+}
+
+func (e event) isExcluded(start time.Time) bool {
+	for _, excludedDate := range e.excludedDates {
+		if excludedDate.isDate && !e.isDate {
+			startYear, startMonth, startDay := start.Date()
+			excludedYear, excludedMonth, excludedDay := excludedDate.value.Date()
+			if startYear == excludedYear && startMonth == excludedMonth && startDay == excludedDay {
+				return true
+			}
+			continue
+		}
+		if excludedDate.value.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e event) isRecurring() bool {
+	return e.recurrenceID.IsZero() && (len(e.rules) > 0 || len(e.extraStarts) > 0)
+}
+
+func parseEvent(lines []contentLine, defaultLocation *time.Location) (event, error) {
+	var parsedEvent event
+	var startLine, endLine, durationLine, recurrenceIDLine *contentLine
+	var ruleLines, extraDateLines, excludedDateLines []contentLine
+
+	for index := range lines {
+		line := &lines[index]
+		switch line.name {
+		case "UID":
+			parsedEvent.uid = line.value
+		case "DTSTART":
+			startLine = line
+		case "DTEND":
+			endLine = line
+		case "DURATION":
+			durationLine = line
+		case "RECURRENCE-ID":
+			recurrenceIDLine = line
+		case "RRULE":
+			ruleLines = append(ruleLines, *line)
+		case "RDATE":
+			extraDateLines = append(extraDateLines, *line)
+		case "EXDATE":
+			excludedDateLines = append(excludedDateLines, *line)
+		case "TRANSP":
+			if strings.EqualFold(line.value, "TRANSPARENT") {
+				parsedEvent.isFree = true
+			}
+		case "STATUS":
+			if strings.EqualFold(line.value, StatusCancelled) {
+				parsedEvent.isFree = true
+			}
+		}
+	}
+
+	// A free occurrence that replaces one of a recurring event still frees
+	// that occurrence, so its RECURRENCE-ID is needed either way.
+	if recurrenceIDLine != nil {
+		if strings.EqualFold(recurrenceIDLine.params["RANGE"], "THISANDFUTURE") {
+			return event{}, fmt.Errorf("%w: unsupported RECURRENCE-ID;RANGE=THISANDFUTURE", ErrInvalidICalendar)
+		}
+		recurrenceID, _, recurrenceIDError := parseDateTime(*recurrenceIDLine, defaultLocation)
+		if recurrenceIDError != nil {
+			return event{}, recurrenceIDError
+		}
+		parsedEvent.recurrenceID = recurrenceID
+	}
+
+	if parsedEvent.isFree {
+		return parsedEvent, nil
+	}
+
+	if startLine == nil {
+		return event{}, fmt.Errorf("%w: VEVENT without DTSTART", ErrInvalidICalendar)
+	}
+
+	start, isDate, startError := parseDateTime(*startLine, defaultLocation)
+	if startError != nil {
+		return event{}, startError
+	}
+	parsedEvent.start = start
+	parsedEvent.isDate = isDate
+
+	switch {
+	case endLine != nil:
+		end, isEndDate, endError := parseDateTime(*endLine, defaultLocation)
+		if endError != nil {
+			return event{}, endError
+		}
+		if isDate && isEndDate {
+			parsedEvent.days = daysBetween(start, end)
+		} else {
+			parsedEvent.duration = end.Sub(start)
+		}
+	case durationLine != nil:
+		duration, durationError := parseDuration(durationLine.value)
+		if durationError != nil {
+			return event{}, durationError
+		}
+		parsedEvent.duration = duration
+	case isDate:
+		parsedEvent.days = 1
+	}
+
+	for _, ruleLine := range ruleLines {
+		rule, ruleError := parseRecurrenceRule(ruleLine.value, start, isDate)
+		if ruleError != nil {
+			return event{}, ruleError
+		}
+		parsedEvent.rules = append(parsedEvent.rules, rule)
+	}
+
+	for _, extraDateLine := range extraDateLines {
+		if strings.EqualFold(extraDateLine.params["VALUE"], "PERIOD") {
+			return event{}, fmt.Errorf("%w: unsupported RDATE;VALUE=PERIOD", ErrInvalidICalendar)
+		}
+		extraDates, extraDatesError := parseDateTimeList(extraDateLine, start.Location())
+		if extraDatesError != nil {
+			return event{}, extraDatesError
+		}
+		for _, extraDate := range extraDates {
+			parsedEvent.extraStarts = append(parsedEvent.extraStarts, extraDate.value)
+		}
+	}
+
+	for _, excludedDateLine := range excludedDateLines {
+		excludedDates, excludedDatesError := parseDateTimeList(excludedDateLine, start.Location())
+		if excludedDatesError != nil {
+			return event{}, excludedDatesError
+		}
+		parsedEvent.excludedDates = append(parsedEvent.excludedDates, excludedDates...)
+	}
+
+	return parsedEvent, nil
+}
+
+// daysBetween counts calendar days rather than 24 hour periods, so that a
+// DST change between two dates does not shorten or lengthen the event.
+func daysBetween(start time.Time, end time.Time) int {
+	startYear, startMonth, startDay := start.Date()
+	endYear, endMonth, endDay := end.Date()
+	startDate := time.Date(startYear, startMonth, startDay, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(endYear, endMonth, endDay, 0, 0, 0, 0, time.UTC)
+	return int(endDate.Sub(startDate) / (24 * time.Hour))
+}
+
+func parseDateTime(line contentLine, defaultLocation *time.Location) (time.Time, bool, error) {
+	location := defaultLocation
+	if timeZoneID, hasTimeZone := line.params["TZID"]; hasTimeZone {
+		if loadedLocation, locationError := time.LoadLocation(timeZoneID); locationError == nil {
+			location = loadedLocation
+		}
+	}
+
+	value := line.value
+	if strings.EqualFold(line.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		parsedDate, parsingError := time.ParseInLocation(dateLayout, value, location)
+		if parsingError != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid date %q", ErrInvalidICalendar, value)
+		}
+		return parsedDate, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		parsedTime, parsingError := time.Parse(utcLayout, value)
+		if parsingError != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid date-time %q", ErrInvalidICalendar, value)
+		}
+		return parsedTime, false, nil
+	}
+
+	parsedTime, parsingError := time.ParseInLocation(localLayout, value, location)
+	if parsingError != nil {
+		return time.Time{}, false, fmt.Errorf("%w: invalid date-time %q", ErrInvalidICalendar, value)
+	}
+	return parsedTime, false, nil
+}
+
+type dateTimeValue struct {
+	value  time.Time
+	isDate bool
+}
+
+// parseDateTimeList reads the comma separated values of RDATE and EXDATE.
+func parseDateTimeList(line contentLine, defaultLocation *time.Location) ([]dateTimeValue, error) {
+	var values []dateTimeValue
+	for value := range strings.SplitSeq(line.value, ",") {
+		valueLine := line
+		valueLine.value = value
+		parsedValue, isDate, parsingError := parseDateTime(valueLine, defaultLocation)
+		if parsingError != nil {
+			return nil, parsingError
+		}
+		values = append(values, dateTimeValue{value: parsedValue, isDate: isDate})
+	}
+	return values, nil
+}
+
+func freeBusyIntervals(line contentLine) ([]Interval, error) {
+	freeBusyType := strings.ToUpper(line.params["FBTYPE"])
+	if freeBusyType == "FREE" {
+		return nil, nil
+	}
+
+	var intervals []Interval
+	for _, period := range strings.Split(line.value, ",") {
+		startValue, endValue, found := strings.Cut(period, "/")
+		if !found {
+			return nil, fmt.Errorf("%w: invalid FREEBUSY period %q", ErrInvalidICalendar, period)
+		}
+
+		start, startError := time.Parse(utcLayout, startValue)
+		if startError != nil {
+			return nil, fmt.Errorf("%w: invalid FREEBUSY period %q", ErrInvalidICalendar, period)
+		}
+
+		var end time.Time
+		if strings.HasPrefix(endValue, "P") || strings.HasPrefix(endValue, "+P") {
+			duration, durationError := parseDuration(endValue)
+			if durationError != nil {
+				return nil, durationError
+			}
+			end = start.Add(duration)
+		} else {
+			parsedEnd, endError := time.Parse(utcLayout, endValue)
+			if endError != nil {
+				return nil, fmt.Errorf("%w: invalid FREEBUSY period %q", ErrInvalidICalendar, period)
+			}
+			end = parsedEnd
+		}
+
+		if end.After(start) {
+			intervals = append(intervals, Interval{Start: start, End: end})
+		}
+	}
+
+	return intervals, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	invalidDuration := fmt.Errorf("%w: invalid DURATION %q", ErrInvalidICalendar, value)
+
+	sign := time.Duration(1)
+	remaining := value
+	switch {
+	case strings.HasPrefix(remaining, "-"):
+		sign = -1
+		remaining = remaining[1:]
+	case strings.HasPrefix(remaining, "+"):
+		remaining = remaining[1:]
+	}
+
+	if !strings.HasPrefix(remaining, "P") || len(remaining) == 1 {
+		return 0, invalidDuration
+	}
+	remaining = remaining[1:]
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+
+	var total time.Duration
+	inTimePart := false
+	hasTimeUnit := false
+	digitsStart := 0
+	for index := 0; index < len(remaining); index++ {
+		character := remaining[index]
+		if character == 'T' {
+			inTimePart = true
+			digitsStart = index + 1
+			continue
+		}
+		if character >= '0' && character <= '9' {
+			continue
+		}
+
+		unit, isUnit := units[character]
+		if !isUnit || digitsStart == index || (inTimePart != (character == 'H' || character == 'M' || character == 'S')) {
+			return 0, invalidDuration
+		}
+
+		amount, parsingError := strconv.Atoi(remaining[digitsStart:index])
+		if parsingError != nil {
+			return 0, invalidDuration
+		}
+		total += time.Duration(amount) * unit
+		hasTimeUnit = hasTimeUnit || inTimePart
+		digitsStart = index + 1
+	}
+
+	// "PT" and "P1DT" name no time unit after the T.
+	if digitsStart != len(remaining) || (inTimePart && !hasTimeUnit) {
+		return 0, invalidDuration
+	}
+
+	return sign * total, nil
+}
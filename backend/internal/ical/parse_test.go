@@ -0,0 +1,288 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendarData wraps lines in a VCALENDAR and joins them with CRLF.
+func calendarData(lines ...string) string {
+	allLines := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	allLines = append(allLines, "END:VCALENDAR", "")
+	return strings.Join(allLines, "\r\n")
+}
+
+func formatIntervals(intervals []Interval) [][2]string {
+	formatted := make([][2]string, 0, len(intervals))
+	for _, interval := range intervals {
+		formatted = append(formatted, [2]string{interval.Start.Format(time.RFC3339), interval.End.Format(time.RFC3339)})
+	}
+	return formatted
+}
+
+func TestParseBusyIntervals(t *testing.T) {
+	warsaw, locationError := time.LoadLocation("Europe/Warsaw")
+	if locationError != nil {
+		t.Fatalf("failed to load Europe/Warsaw: %v", locationError)
+	}
+
+	testCases := []struct {
+		name     string
+		data     string
+		expected [][2]string
+	}{
+		{
+			name: "UTC times",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"DTEND:20260110T100000Z",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T09:00:00Z", "2026-01-10T10:00:00Z"}},
+		},
+		{
+			name: "folded lines are unfolded",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"SUMMARY:A summary long enough to be",
+				"  folded",
+				"DTSTART:20260110T09",
+				" 0000Z",
+				"DTEND:2026011",
+				"\t0T100000Z",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T09:00:00Z", "2026-01-10T10:00:00Z"}},
+		},
+		{
+			name: "LF line endings",
+			data: strings.ReplaceAll(calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"DTEND:20260110T100000Z",
+				"END:VEVENT",
+			), "\r\n", "\n"),
+			expected: [][2]string{{"2026-01-10T09:00:00Z", "2026-01-10T10:00:00Z"}},
+		},
+		{
+			name: "TZID",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=America/New_York:20260110T090000",
+				`DTEND;TZID="America/New_York":20260110T100000`,
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T09:00:00-05:00", "2026-01-10T10:00:00-05:00"}},
+		},
+		{
+			name: "TZID that is not an IANA name uses the default location",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=Central European Standard Time:20260110T090000",
+				"DTEND;TZID=Central European Standard Time:20260110T100000",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T09:00:00+01:00", "2026-01-10T10:00:00+01:00"}},
+		},
+		{
+			name: "floating times use the default location",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260710T090000",
+				"DTEND:20260710T100000",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-07-10T09:00:00+02:00", "2026-07-10T10:00:00+02:00"}},
+		},
+		{
+			name: "VALUE=DATE with DTEND",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20260110",
+				"DTEND;VALUE=DATE:20260112",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T00:00:00+01:00", "2026-01-12T00:00:00+01:00"}},
+		},
+		{
+			name: "VALUE=DATE without DTEND lasts one day",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20260110",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T00:00:00+01:00", "2026-01-11T00:00:00+01:00"}},
+		},
+		{
+			name: "DURATION without DTEND",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"DURATION:PT1H30M",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20260112",
+				"DURATION:P2D",
+				"END:VEVENT",
+			),
+			expected: [][2]string{
+				{"2026-01-10T09:00:00Z", "2026-01-10T10:30:00Z"},
+				{"2026-01-12T00:00:00+01:00", "2026-01-14T00:00:00+01:00"},
+			},
+		},
+		{
+			name: "date-time without DTEND or DURATION takes no time",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"END:VEVENT",
+			),
+			expected: [][2]string{},
+		},
+		{
+			name: "FREEBUSY periods",
+			data: calendarData(
+				"BEGIN:VFREEBUSY",
+				"FREEBUSY:20260110T090000Z/20260110T100000Z,20260110T120000Z/PT30M",
+				"FREEBUSY;FBTYPE=BUSY-TENTATIVE:20260111T090000Z/20260111T100000Z",
+				"FREEBUSY;FBTYPE=FREE:20260112T090000Z/20260112T100000Z",
+				"END:VFREEBUSY",
+			),
+			expected: [][2]string{
+				{"2026-01-10T09:00:00Z", "2026-01-10T10:00:00Z"},
+				{"2026-01-10T12:00:00Z", "2026-01-10T12:30:00Z"},
+				{"2026-01-11T09:00:00Z", "2026-01-11T10:00:00Z"},
+			},
+		},
+		{
+			name: "TRANSP:TRANSPARENT events are free",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"DTEND:20260110T100000Z",
+				"TRANSP:TRANSPARENT",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T110000Z",
+				"DTEND:20260110T120000Z",
+				"TRANSP:OPAQUE",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T11:00:00Z", "2026-01-10T12:00:00Z"}},
+		},
+		{
+			name: "STATUS:CANCELLED events are free",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"DTEND:20260110T100000Z",
+				"STATUS:CANCELLED",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T110000Z",
+				"DTEND:20260110T120000Z",
+				"STATUS:TENTATIVE",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T11:00:00Z", "2026-01-10T12:00:00Z"}},
+		},
+		{
+			name: "nested components do not leak into the event",
+			data: calendarData(
+				"BEGIN:VEVENT",
+				"DTSTART:20260110T090000Z",
+				"DTEND:20260110T100000Z",
+				"BEGIN:VALARM",
+				"TRIGGER:-PT15M",
+				"ACTION:DISPLAY",
+				"END:VALARM",
+				"END:VEVENT",
+			),
+			expected: [][2]string{{"2026-01-10T09:00:00Z", "2026-01-10T10:00:00Z"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			intervals, parsingError := ParseBusyIntervals(strings.NewReader(testCase.data), warsaw, Interval{})
+			if parsingError != nil {
+				t.Fatalf("ParseBusyIntervals: %v", parsingError)
+			}
+
+			formatted := formatIntervals(intervals)
+			if len(formatted) != len(testCase.expected) {
+				t.Fatalf("got %d intervals %v, want %v", len(formatted), formatted, testCase.expected)
+			}
+			for index := range formatted {
+				if formatted[index] != testCase.expected[index] {
+					t.Errorf("interval %d = %v, want %v", index, formatted[index], testCase.expected[index])
+				}
+			}
+		})
+	}
+}
+
+func TestParseBusyIntervalsRejectsInvalidData(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "missing VCALENDAR", data: "BEGIN:VEVENT\r\nDTSTART:20260110T090000Z\r\nEND:VEVENT\r\n"},
+		{name: "unterminated component", data: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20260110T090000Z\r\n"},
+		{name: "mismatched END", data: calendarData("BEGIN:VEVENT", "END:VTODO")},
+		{name: "event without DTSTART", data: calendarData("BEGIN:VEVENT", "DTEND:20260110T100000Z", "END:VEVENT")},
+		{name: "line without a colon", data: calendarData("BEGIN:VEVENT", "DTSTART", "END:VEVENT")},
+		{name: "invalid date-time", data: calendarData("BEGIN:VEVENT", "DTSTART:2026-01-10T09:00:00Z", "END:VEVENT")},
+		{name: "invalid DURATION", data: calendarData("BEGIN:VEVENT", "DTSTART:20260110T090000Z", "DURATION:1H", "END:VEVENT")},
+		{name: "invalid FREEBUSY period", data: calendarData("BEGIN:VFREEBUSY", "FREEBUSY:20260110T090000Z", "END:VFREEBUSY")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, parsingError := ParseBusyIntervals(strings.NewReader(testCase.data), time.UTC, Interval{})
+			if !errors.Is(parsingError, ErrInvalidICalendar) {
+				t.Errorf("ParseBusyIntervals = %v, want %v", parsingError, ErrInvalidICalendar)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		isValid  bool
+	}{
+		{value: "PT15M", expected: 15 * time.Minute, isValid: true},
+		{value: "PT1H30M", expected: 90 * time.Minute, isValid: true},
+		{value: "P1DT2H", expected: 26 * time.Hour, isValid: true},
+		{value: "P1W", expected: 7 * 24 * time.Hour, isValid: true},
+		{value: "+PT10S", expected: 10 * time.Second, isValid: true},
+		{value: "-PT15M", expected: -15 * time.Minute, isValid: true},
+		{value: "P"},
+		{value: "PT"},
+		{value: "P1DT"},
+		{value: "P1H"},
+		{value: "PT1D"},
+		{value: "PT1H30"},
+		{value: "1H"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			duration, parsingError := parseDuration(testCase.value)
+			if !testCase.isValid {
+				if !errors.Is(parsingError, ErrInvalidICalendar) {
+					t.Errorf("parseDuration(%q) = %v, %v; want %v", testCase.value, duration, parsingError, ErrInvalidICalendar)
+				}
+				return
+			}
+			if parsingError != nil || duration != testCase.expected {
+				t.Errorf("parseDuration(%q) = %v, %v; want %v", testCase.value, duration, parsingError, testCase.expected)
+			}
+		})
+	}
+}
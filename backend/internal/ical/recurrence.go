@@ -0,0 +1,336 @@
+package ical
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecurrencePeriods bounds the days, weeks, months or years walked while
+// expanding all the recurring events of one file.
+const maxRecurrencePeriods = 100_000
+
+var weekdayNames = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type weekdayRule struct {
+	// ordinal selects the nth (or, when negative, the nth last) matching
+	// weekday of the month. Zero selects every matching weekday.
+	ordinal int
+	weekday time.Weekday
+}
+
+// recurrenceRule is an RRULE with FREQ DAILY, WEEKLY, MONTHLY or YEARLY and
+// the parts INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST.
+type recurrenceRule struct {
+	frequency  string
+	interval   int
+	count      int
+	until      time.Time
+	weekStart  time.Weekday
+	byDay      []weekdayRule
+	byMonthDay []int
+	byMonth    []time.Month
+}
+
+func parseRecurrenceRule(value string, start time.Time, isDate bool) (recurrenceRule, error) {
+	rule := recurrenceRule{interval: 1, weekStart: time.Monday}
+	invalidRule := func(reason string) error {
+		return fmt.Errorf("%w: RRULE %q %s", ErrInvalidICalendar, value, reason)
+	}
+
+	for part := range strings.SplitSeq(value, ";") {
+		partName, partValue, _ := strings.Cut(part, "=")
+		partName = strings.ToUpper(partName)
+		partValue = strings.ToUpper(partValue)
+
+		switch partName {
+		case "FREQ":
+			if partValue != "DAILY" && partValue != "WEEKLY" && partValue != "MONTHLY" && partValue != "YEARLY" {
+				return recurrenceRule{}, invalidRule("has an unsupported FREQ")
+			}
+			rule.frequency = partValue
+		case "INTERVAL":
+			interval, parsingError := strconv.Atoi(partValue)
+			if parsingError != nil || interval < 1 {
+				return recurrenceRule{}, invalidRule("has an invalid INTERVAL")
+			}
+			rule.interval = interval
+		case "COUNT":
+			count, parsingError := strconv.Atoi(partValue)
+			if parsingError != nil || count < 1 {
+				return recurrenceRule{}, invalidRule("has an invalid COUNT")
+			}
+			rule.count = count
+		case "UNTIL":
+			until, isUntilDate, untilError := parseDateTime(contentLine{value: partValue}, start.Location())
+			if untilError != nil {
+				return recurrenceRule{}, untilError
+			}
+			// A date UNTIL on an event with a time includes that whole day.
+			if isUntilDate && !isDate {
+				until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			rule.until = until
+		case "WKST":
+			weekStart, isWeekday := weekdayNames[partValue]
+			if !isWeekday {
+				return recurrenceRule{}, invalidRule("has an invalid WKST")
+			}
+			rule.weekStart = weekStart
+		case "BYDAY":
+			for dayValue := range strings.SplitSeq(partValue, ",") {
+				if len(dayValue) < 2 {
+					return recurrenceRule{}, invalidRule("has an invalid BYDAY")
+				}
+				weekday, isWeekday := weekdayNames[dayValue[len(dayValue)-2:]]
+				if !isWeekday {
+					return recurrenceRule{}, invalidRule("has an invalid BYDAY")
+				}
+				dayRule := weekdayRule{weekday: weekday}
+				if ordinalText := dayValue[:len(dayValue)-2]; ordinalText != "" {
+					ordinal, parsingError := strconv.Atoi(ordinalText)
+					if parsingError != nil || ordinal == 0 || ordinal < -53 || ordinal > 53 {
+						return recurrenceRule{}, invalidRule("has an invalid BYDAY")
+					}
+					dayRule.ordinal = ordinal
+				}
+				rule.byDay = append(rule.byDay, dayRule)
+			}
+		case "BYMONTHDAY":
+			for dayValue := range strings.SplitSeq(partValue, ",") {
+				monthDay, parsingError := strconv.Atoi(dayValue)
+				if parsingError != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return recurrenceRule{}, invalidRule("has an invalid BYMONTHDAY")
+				}
+				rule.byMonthDay = append(rule.byMonthDay, monthDay)
+			}
+		case "BYMONTH":
+			for monthValue := range strings.SplitSeq(partValue, ",") {
+				month, parsingError := strconv.Atoi(monthValue)
+				if parsingError != nil || month < 1 || month > 12 {
+					return recurrenceRule{}, invalidRule("has an invalid BYMONTH")
+				}
+				rule.byMonth = append(rule.byMonth, time.Month(month))
+			}
+		default:
+			return recurrenceRule{}, invalidRule(fmt.Sprintf("uses %s, which is not supported", partName))
+		}
+	}
+
+	if rule.frequency == "" {
+		return recurrenceRule{}, invalidRule("has no FREQ")
+	}
+	if rule.count > 0 && !rule.until.IsZero() {
+		return recurrenceRule{}, invalidRule("has both COUNT and UNTIL")
+	}
+	if rule.frequency == "WEEKLY" && len(rule.byMonthDay) > 0 {
+		return recurrenceRule{}, invalidRule("has BYMONTHDAY in a WEEKLY rule")
+	}
+	if rule.hasOrdinalWeekdays() && rule.frequency != "MONTHLY" && (rule.frequency != "YEARLY" || len(rule.byMonth) == 0) {
+		return recurrenceRule{}, invalidRule("numbers BYDAY weekdays, which is supported only in MONTHLY rules and YEARLY rules with BYMONTH")
+	}
+
+	return rule, nil
+}
+
+func (r recurrenceRule) hasOrdinalWeekdays() bool {
+	return slices.ContainsFunc(r.byDay, func(dayRule weekdayRule) bool {
+		return dayRule.ordinal != 0
+	})
+}
+
+// expand returns the starts of the occurrences that begin before windowEnd,
+// DTSTART included. Occurrences before the window still count towards COUNT.
+func (r recurrenceRule) expand(start time.Time, windowEnd time.Time, remainingPeriods *int) ([]time.Time, error) {
+	starts := []time.Time{start}
+	occurrenceCount := 1
+
+	for periodIndex := 0; ; periodIndex++ {
+		if *remainingPeriods <= 0 {
+			return nil, fmt.Errorf("%w: recurring events repeat too many times before the calendar's time slots", ErrInvalidICalendar)
+		}
+		*remainingPeriods--
+
+		periodStart, candidates := r.period(start, periodIndex)
+		if !periodStart.Before(windowEnd) || (!r.until.IsZero() && periodStart.After(r.until)) {
+			return starts, nil
+		}
+
+		for _, candidate := range candidates {
+			if !candidate.After(start) {
+				continue
+			}
+			if (!r.until.IsZero() && candidate.After(r.until)) || (r.count > 0 && occurrenceCount >= r.count) {
+				return starts, nil
+			}
+			occurrenceCount++
+			if candidate.Before(windowEnd) {
+				starts = append(starts, candidate)
+			}
+		}
+	}
+}
+
+// period returns the first day of the periodIndex-th day, week, month or
+// year of the rule and the starts of the occurrences in it, in order.
+func (r recurrenceRule) period(start time.Time, periodIndex int) (time.Time, []time.Time) {
+	startYear, startMonth, startDay := start.Date()
+	location := start.Location()
+	occurrenceOn := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, location)
+	}
+
+	var candidates []time.Time
+
+	switch r.frequency {
+	case "DAILY":
+		periodStart := time.Date(startYear, startMonth, startDay+periodIndex*r.interval, 0, 0, 0, 0, location)
+		year, month, day := periodStart.Date()
+		if r.includesMonth(month) && slices.Contains(r.monthDays(year, month, day), day) {
+			candidates = append(candidates, occurrenceOn(year, month, day))
+		}
+		return periodStart, candidates
+	case "WEEKLY":
+		daysSinceWeekStart := (int(start.Weekday()) - int(r.weekStart) + 7) % 7
+		periodStart := time.Date(startYear, startMonth, startDay-daysSinceWeekStart+7*periodIndex*r.interval, 0, 0, 0, 0, location)
+		for dayIndex := range 7 {
+			year, month, day := periodStart.AddDate(0, 0, dayIndex).Date()
+			weekday := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+			if !r.includesMonth(month) {
+				continue
+			}
+			if (len(r.byDay) == 0 && weekday == start.Weekday()) || slices.ContainsFunc(r.byDay, func(dayRule weekdayRule) bool {
+				return dayRule.weekday == weekday
+			}) {
+				candidates = append(candidates, occurrenceOn(year, month, day))
+			}
+		}
+		return periodStart, candidates
+	case "MONTHLY":
+		periodStart := time.Date(startYear, startMonth+time.Month(periodIndex*r.interval), 1, 0, 0, 0, 0, location)
+		year, month, _ := periodStart.Date()
+		if r.includesMonth(month) {
+			for _, day := range r.monthDays(year, month, startDay) {
+				candidates = append(candidates, occurrenceOn(year, month, day))
+			}
+		}
+		return periodStart, candidates
+	default:
+		year := startYear + periodIndex*r.interval
+		periodStart := time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{startMonth}
+			if len(r.byDay) > 0 || len(r.byMonthDay) > 0 {
+				months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			}
+		}
+		for _, month := range slices.Sorted(slices.Values(months)) {
+			for _, day := range r.monthDays(year, month, startDay) {
+				candidates = append(candidates, occurrenceOn(year, month, day))
+			}
+		}
+		return periodStart, candidates
+	}
+}
+
+func (r recurrenceRule) includesMonth(month time.Month) bool {
+	return len(r.byMonth) == 0 || slices.Contains(r.byMonth, month)
+}
+
+// monthDays returns the days of the month that BYMONTHDAY and BYDAY select,
+// or startDay when the rule has neither. Days the month does not have, such
+// as the 31st of April, are skipped rather than moved to the next month.
+func (r recurrenceRule) monthDays(year int, month time.Month, startDay int) []int {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		if startDay > daysInMonth {
+			return nil
+		}
+		return []int{startDay}
+	}
+
+	var days []int
+	for day := 1; day <= daysInMonth; day++ {
+		matchesMonthDay := len(r.byMonthDay) == 0 || slices.ContainsFunc(r.byMonthDay, func(monthDay int) bool {
+			return monthDay == day || monthDay == day-daysInMonth-1
+		})
+		weekday := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+		matchesWeekday := len(r.byDay) == 0 || slices.ContainsFunc(r.byDay, func(dayRule weekdayRule) bool {
+			switch {
+			case dayRule.weekday != weekday:
+				return false
+			case dayRule.ordinal > 0:
+				return (day-1)/7+1 == dayRule.ordinal
+			case dayRule.ordinal < 0:
+				return (daysInMonth-day)/7+1 == -dayRule.ordinal
+			}
+			return true
+		})
+		if matchesMonthDay && matchesWeekday {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// busyEventIntervals turns parsed events into busy intervals. Occurrences of
+// recurring events are limited to window, single events are not.
+func busyEventIntervals(events []event, window Interval) ([]Interval, error) {
+	replacedStarts := map[string][]time.Time{}
+	for _, parsedEvent := range events {
+		if !parsedEvent.recurrenceID.IsZero() && parsedEvent.uid != "" {
+			replacedStarts[parsedEvent.uid] = append(replacedStarts[parsedEvent.uid], parsedEvent.recurrenceID)
+		}
+	}
+
+	intervals := []Interval{}
+	remainingPeriods := maxRecurrencePeriods
+
+	for _, parsedEvent := range events {
+		if parsedEvent.isFree {
+			continue
+		}
+
+		if !parsedEvent.isRecurring() {
+			if end := parsedEvent.endOf(parsedEvent.start); end.After(parsedEvent.start) {
+				intervals = append(intervals, Interval{Start: parsedEvent.start, End: end})
+			}
+			continue
+		}
+
+		starts := append([]time.Time{parsedEvent.start}, parsedEvent.extraStarts...)
+		for _, rule := range parsedEvent.rules {
+			ruleStarts, expansionError := rule.expand(parsedEvent.start, window.End, &remainingPeriods)
+			if expansionError != nil {
+				return nil, expansionError
+			}
+			starts = append(starts, ruleStarts...)
+		}
+		slices.SortFunc(starts, time.Time.Compare)
+		starts = slices.CompactFunc(starts, time.Time.Equal)
+
+		for _, start := range starts {
+			if parsedEvent.isExcluded(start) || slices.ContainsFunc(replacedStarts[parsedEvent.uid], start.Equal) {
+				continue
+			}
+			occurrence := Interval{Start: start, End: parsedEvent.endOf(start)}
+			if occurrence.End.After(occurrence.Start) && occurrence.Overlaps(window.Start, window.End) {
+				intervals = append(intervals, occurrence)
+			}
+		}
+	}
+
+	return intervals, nil
+}
@@ -0,0 +1,351 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseInterval(t *testing.T, start string, end string) Interval {
+	t.Helper()
+
+	startTime, startError := time.Parse(time.RFC3339, start)
+	endTime, endError := time.Parse(time.RFC3339, end)
+	if startError != nil || endError != nil {
+		t.Fatalf("invalid interval %s - %s", start, end)
+	}
+	return Interval{Start: startTime, End: endTime}
+}
+
+func TestParseBusyIntervalsExpandsRecurrences(t *testing.T) {
+	warsaw, locationError := time.LoadLocation("Europe/Warsaw")
+	if locationError != nil {
+		t.Fatalf("failed to load Europe/Warsaw: %v", locationError)
+	}
+
+	testCases := []struct {
+		name     string
+		lines    []string
+		window   [2]string
+		expected [][2]string
+	}{
+		{
+			name: "weekly on two days with COUNT",
+			lines: []string{
+				"DTSTART:20260105T090000Z",
+				"DTEND:20260105T100000Z",
+				"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			},
+			expected: [][2]string{
+				{"2026-01-05T09:00:00Z", "2026-01-05T10:00:00Z"},
+				{"2026-01-07T09:00:00Z", "2026-01-07T10:00:00Z"},
+				{"2026-01-12T09:00:00Z", "2026-01-12T10:00:00Z"},
+				{"2026-01-14T09:00:00Z", "2026-01-14T10:00:00Z"},
+			},
+		},
+		{
+			name: "EXDATE removes occurrences",
+			lines: []string{
+				"DTSTART:20260105T090000Z",
+				"DTEND:20260105T100000Z",
+				"RRULE:FREQ=DAILY;COUNT=4",
+				"EXDATE:20260106T090000Z,20260108T090000Z",
+			},
+			expected: [][2]string{
+				{"2026-01-05T09:00:00Z", "2026-01-05T10:00:00Z"},
+				{"2026-01-07T09:00:00Z", "2026-01-07T10:00:00Z"},
+			},
+		},
+		{
+			name: "EXDATE as a date removes that day",
+			lines: []string{
+				"DTSTART;TZID=Europe/Warsaw:20260105T090000",
+				"DTEND;TZID=Europe/Warsaw:20260105T100000",
+				"RRULE:FREQ=DAILY;COUNT=3",
+				"EXDATE;VALUE=DATE:20260106",
+			},
+			expected: [][2]string{
+				{"2026-01-05T09:00:00+01:00", "2026-01-05T10:00:00+01:00"},
+				{"2026-01-07T09:00:00+01:00", "2026-01-07T10:00:00+01:00"},
+			},
+		},
+		{
+			name: "daily with INTERVAL and UNTIL",
+			lines: []string{
+				"DTSTART:20260105T090000Z",
+				"DURATION:PT1H",
+				"RRULE:FREQ=DAILY;INTERVAL=2;UNTIL=20260109T090000Z",
+			},
+			expected: [][2]string{
+				{"2026-01-05T09:00:00Z", "2026-01-05T10:00:00Z"},
+				{"2026-01-07T09:00:00Z", "2026-01-07T10:00:00Z"},
+				{"2026-01-09T09:00:00Z", "2026-01-09T10:00:00Z"},
+			},
+		},
+		{
+			name: "UNTIL as a date includes that day",
+			lines: []string{
+				"DTSTART:20260105T090000Z",
+				"DURATION:PT1H",
+				"RRULE:FREQ=DAILY;UNTIL=20260106",
+			},
+			expected: [][2]string{
+				{"2026-01-05T09:00:00Z", "2026-01-05T10:00:00Z"},
+				{"2026-01-06T09:00:00Z", "2026-01-06T10:00:00Z"},
+			},
+		},
+		{
+			name: "endless rule is expanded over the window only",
+			lines: []string{
+				"DTSTART:20250602T090000Z",
+				"DTEND:20250602T100000Z",
+				"RRULE:FREQ=WEEKLY",
+			},
+			window: [2]string{"2026-01-10T00:00:00Z", "2026-01-27T00:00:00Z"},
+			expected: [][2]string{
+				{"2026-01-12T09:00:00Z", "2026-01-12T10:00:00Z"},
+				{"2026-01-19T09:00:00Z", "2026-01-19T10:00:00Z"},
+				{"2026-01-26T09:00:00Z", "2026-01-26T10:00:00Z"},
+			},
+		},
+		{
+			name: "occurrences before the window count towards COUNT",
+			lines: []string{
+				"DTSTART:20251230T090000Z",
+				"DTEND:20251230T100000Z",
+				"RRULE:FREQ=DAILY;COUNT=4",
+			},
+			expected: [][2]string{
+				{"2026-01-01T09:00:00Z", "2026-01-01T10:00:00Z"},
+				{"2026-01-02T09:00:00Z", "2026-01-02T10:00:00Z"},
+			},
+		},
+		{
+			name: "last Friday of the month",
+			lines: []string{
+				"DTSTART:20260130T150000Z",
+				"DTEND:20260130T160000Z",
+				"RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			},
+			window: [2]string{"2026-01-01T00:00:00Z", "2026-04-01T00:00:00Z"},
+			expected: [][2]string{
+				{"2026-01-30T15:00:00Z", "2026-01-30T16:00:00Z"},
+				{"2026-02-27T15:00:00Z", "2026-02-27T16:00:00Z"},
+				{"2026-03-27T15:00:00Z", "2026-03-27T16:00:00Z"},
+			},
+		},
+		{
+			name: "second Tuesday of the month",
+			lines: []string{
+				"DTSTART:20260113T150000Z",
+				"DTEND:20260113T160000Z",
+				"RRULE:FREQ=MONTHLY;BYDAY=2TU;COUNT=2",
+			},
+			window: [2]string{"2026-01-01T00:00:00Z", "2026-03-01T00:00:00Z"},
+			expected: [][2]string{
+				{"2026-01-13T15:00:00Z", "2026-01-13T16:00:00Z"},
+				{"2026-02-10T15:00:00Z", "2026-02-10T16:00:00Z"},
+			},
+		},
+		{
+			name: "monthly on the 31st skips shorter months",
+			lines: []string{
+				"DTSTART:20260131T090000Z",
+				"DTEND:20260131T100000Z",
+				"RRULE:FREQ=MONTHLY;COUNT=3",
+			},
+			window: [2]string{"2026-01-01T00:00:00Z", "2026-07-01T00:00:00Z"},
+			expected: [][2]string{
+				{"2026-01-31T09:00:00Z", "2026-01-31T10:00:00Z"},
+				{"2026-03-31T09:00:00Z", "2026-03-31T10:00:00Z"},
+				{"2026-05-31T09:00:00Z", "2026-05-31T10:00:00Z"},
+			},
+		},
+		{
+			name: "yearly on February 29",
+			lines: []string{
+				"DTSTART:20240229T090000Z",
+				"DTEND:20240229T100000Z",
+				"RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29;COUNT=2",
+			},
+			window: [2]string{"2024-01-01T00:00:00Z", "2029-01-01T00:00:00Z"},
+			expected: [][2]string{
+				{"2024-02-29T09:00:00Z", "2024-02-29T10:00:00Z"},
+				{"2028-02-29T09:00:00Z", "2028-02-29T10:00:00Z"},
+			},
+		},
+		{
+			// Both WKST examples of RFC 5545, section 3.3.10.
+			name: "week starting on Monday",
+			lines: []string{
+				"DTSTART;TZID=America/New_York:19970805T090000",
+				"DURATION:PT1H",
+				"RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			},
+			window: [2]string{"1997-08-01T00:00:00Z", "1997-10-01T00:00:00Z"},
+			expected: [][2]string{
+				{"1997-08-05T09:00:00-04:00", "1997-08-05T10:00:00-04:00"},
+				{"1997-08-10T09:00:00-04:00", "1997-08-10T10:00:00-04:00"},
+				{"1997-08-19T09:00:00-04:00", "1997-08-19T10:00:00-04:00"},
+				{"1997-08-24T09:00:00-04:00", "1997-08-24T10:00:00-04:00"},
+			},
+		},
+		{
+			name: "week starting on Sunday",
+			lines: []string{
+				"DTSTART;TZID=America/New_York:19970805T090000",
+				"DURATION:PT1H",
+				"RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			},
+			window: [2]string{"1997-08-01T00:00:00Z", "1997-10-01T00:00:00Z"},
+			expected: [][2]string{
+				{"1997-08-05T09:00:00-04:00", "1997-08-05T10:00:00-04:00"},
+				{"1997-08-17T09:00:00-04:00", "1997-08-17T10:00:00-04:00"},
+				{"1997-08-19T09:00:00-04:00", "1997-08-19T10:00:00-04:00"},
+				{"1997-08-31T09:00:00-04:00", "1997-08-31T10:00:00-04:00"},
+			},
+		},
+		{
+			name: "wall-clock time is kept across a DST change",
+			lines: []string{
+				"DTSTART;TZID=Europe/Warsaw:20260323T090000",
+				"DTEND;TZID=Europe/Warsaw:20260323T100000",
+				"RRULE:FREQ=WEEKLY;COUNT=2",
+			},
+			window: [2]string{"2026-03-01T00:00:00Z", "2026-04-01T00:00:00Z"},
+			expected: [][2]string{
+				{"2026-03-23T09:00:00+01:00", "2026-03-23T10:00:00+01:00"},
+				{"2026-03-30T09:00:00+02:00", "2026-03-30T10:00:00+02:00"},
+			},
+		},
+		{
+			name: "date events recur as whole days",
+			lines: []string{
+				"DTSTART;VALUE=DATE:20260324",
+				"DTEND;VALUE=DATE:20260325",
+				"RRULE:FREQ=WEEKLY;COUNT=2",
+			},
+			window: [2]string{"2026-03-01T00:00:00Z", "2026-04-10T00:00:00Z"},
+			expected: [][2]string{
+				{"2026-03-24T00:00:00+01:00", "2026-03-25T00:00:00+01:00"},
+				{"2026-03-31T00:00:00+02:00", "2026-04-01T00:00:00+02:00"},
+			},
+		},
+		{
+			name: "RDATE adds occurrences",
+			lines: []string{
+				"DTSTART:20260105T090000Z",
+				"DTEND:20260105T100000Z",
+				"RDATE:20260110T090000Z,20260112T120000Z",
+			},
+			expected: [][2]string{
+				{"2026-01-05T09:00:00Z", "2026-01-05T10:00:00Z"},
+				{"2026-01-10T09:00:00Z", "2026-01-10T10:00:00Z"},
+				{"2026-01-12T12:00:00Z", "2026-01-12T13:00:00Z"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			window := mustParseInterval(t, "2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z")
+			if testCase.window != [2]string{} {
+				window = mustParseInterval(t, testCase.window[0], testCase.window[1])
+			}
+
+			eventLines := append([]string{"BEGIN:VEVENT", "UID:recurring@example.com"}, testCase.lines...)
+			eventLines = append(eventLines, "END:VEVENT")
+
+			intervals, parsingError := ParseBusyIntervals(strings.NewReader(calendarData(eventLines...)), warsaw, window)
+			if parsingError != nil {
+				t.Fatalf("ParseBusyIntervals: %v", parsingError)
+			}
+
+			formatted := formatIntervals(intervals)
+			if len(formatted) != len(testCase.expected) {
+				t.Fatalf("got %d intervals %v, want %v", len(formatted), formatted, testCase.expected)
+			}
+			for index := range formatted {
+				if formatted[index] != testCase.expected[index] {
+					t.Errorf("interval %d = %v, want %v", index, formatted[index], testCase.expected[index])
+				}
+			}
+		})
+	}
+}
+
+func TestParseBusyIntervalsAppliesRecurrenceOverrides(t *testing.T) {
+	data := calendarData(
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"DTSTART:20260105T090000Z",
+		"DTEND:20260105T100000Z",
+		"RRULE:FREQ=DAILY;COUNT=3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID:20260106T090000Z",
+		"DTSTART:20260106T140000Z",
+		"DTEND:20260106T150000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID:20260107T090000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+	)
+	window := mustParseInterval(t, "2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z")
+
+	intervals, parsingError := ParseBusyIntervals(strings.NewReader(data), time.UTC, window)
+	if parsingError != nil {
+		t.Fatalf("ParseBusyIntervals: %v", parsingError)
+	}
+
+	expected := [][2]string{
+		{"2026-01-05T09:00:00Z", "2026-01-05T10:00:00Z"},
+		{"2026-01-06T14:00:00Z", "2026-01-06T15:00:00Z"},
+	}
+	formatted := formatIntervals(intervals)
+	if len(formatted) != len(expected) {
+		t.Fatalf("got %v, want %v", formatted, expected)
+	}
+	for index := range formatted {
+		if formatted[index] != expected[index] {
+			t.Errorf("interval %d = %v, want %v", index, formatted[index], expected[index])
+		}
+	}
+}
+
+func TestParseBusyIntervalsRejectsUnsupportedRecurrences(t *testing.T) {
+	testCases := []struct {
+		name  string
+		lines []string
+	}{
+		{name: "hourly frequency", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=HOURLY"}},
+		{name: "BYSETPOS", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=MONTHLY;BYDAY=MO,TU;BYSETPOS=-1"}},
+		{name: "BYHOUR", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=DAILY;BYHOUR=9,17"}},
+		{name: "missing FREQ", lines: []string{"DTSTART:20260105T090000Z", "RRULE:COUNT=3"}},
+		{name: "COUNT and UNTIL", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=DAILY;COUNT=3;UNTIL=20260110T000000Z"}},
+		{name: "numbered weekday in a weekly rule", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=WEEKLY;BYDAY=1MO"}},
+		{name: "numbered weekday in a yearly rule without BYMONTH", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=YEARLY;BYDAY=20MO"}},
+		{name: "invalid weekday", lines: []string{"DTSTART:20260105T090000Z", "RRULE:FREQ=WEEKLY;BYDAY=XX"}},
+		{name: "RECURRENCE-ID with RANGE", lines: []string{"DTSTART:20260105T090000Z", "RECURRENCE-ID;RANGE=THISANDFUTURE:20260105T090000Z"}},
+		{name: "RDATE periods", lines: []string{"DTSTART:20260105T090000Z", "RDATE;VALUE=PERIOD:20260110T090000Z/PT1H"}},
+		{name: "too many periods before the window", lines: []string{"DTSTART:17000101T090000Z", "DURATION:PT1H", "RRULE:FREQ=DAILY"}},
+	}
+
+	window := mustParseInterval(t, "2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z")
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eventLines := append([]string{"BEGIN:VEVENT"}, testCase.lines...)
+			eventLines = append(eventLines, "END:VEVENT")
+
+			_, parsingError := ParseBusyIntervals(strings.NewReader(calendarData(eventLines...)), time.UTC, window)
+			if !errors.Is(parsingError, ErrInvalidICalendar) {
+				t.Errorf("ParseBusyIntervals = %v, want %v", parsingError, ErrInvalidICalendar)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver finds the address of the client behind a chain of trusted
+// proxies. X-Forwarded-For is only honoured when the direct peer is trusted,
+// and it is read from the right so that a client cannot spoof its address by
+// sending its own header.
+type ClientIPResolver struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewClientIPResolver accepts single addresses and CIDR ranges.
+func NewClientIPResolver(trustedProxies []string) (*ClientIPResolver, error) {
+	resolver := &ClientIPResolver{}
+
+	for _, trustedProxy := range trustedProxies {
+		trustedProxy = strings.TrimSpace(trustedProxy)
+		if trustedProxy == "" {
+			continue
+		}
+
+		if strings.Contains(trustedProxy, "/") {
+			prefix, prefixError := netip.ParsePrefix(trustedProxy)
+			if prefixError != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", trustedProxy, prefixError)
+			}
+			resolver.trustedProxies = append(resolver.trustedProxies, prefix.Masked())
+			continue
+		}
+
+		address, addressError := netip.ParseAddr(trustedProxy)
+		if addressError != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", trustedProxy, addressError)
+		}
+		address = address.Unmap()
+		resolver.trustedProxies = append(resolver.trustedProxies, netip.PrefixFrom(address, address.BitLen()))
+	}
+
+	return resolver, nil
+}
+
+func (r *ClientIPResolver) ClientIP(request *http.Request) string {
+	remoteHost, _, splitError := net.SplitHostPort(request.RemoteAddr)
+	if splitError != nil {
+		remoteHost = request.RemoteAddr
+	}
+
+	remoteAddress, parsingError := netip.ParseAddr(remoteHost)
+	if parsingError != nil {
+		return remoteHost
+	}
+	clientAddress := remoteAddress.Unmap()
+
+	if !r.isTrusted(clientAddress) {
+		return clientAddress.String()
+	}
+
+	forwardedHops := strings.Split(strings.Join(request.Header.Values("X-Forwarded-For"), ","), ",")
+	for index := len(forwardedHops) - 1; index >= 0; index-- {
+		hopAddress, hopError := netip.ParseAddr(strings.TrimSpace(forwardedHops[index]))
+		if hopError != nil {
+			break
+		}
+
+		clientAddress = hopAddress.Unmap()
+		if !r.isTrusted(clientAddress) {
+			break
+		}
+	}
+
+	return clientAddress.String()
+}
+
+func (r *ClientIPResolver) isTrusted(address netip.Addr) bool {
+	for _, trustedProxy := range r.trustedProxies {
+		if trustedProxy.Contains(address) {
+			return true
+		}
+	}
+	return false
+}
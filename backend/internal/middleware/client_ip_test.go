@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolverClientIP(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string
+		expected       string
+	}{
+		{
+			name:       "no trusted proxies uses the peer",
+			remoteAddr: "203.0.113.7:51234",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:         "untrusted peer cannot forward",
+			remoteAddr:   "203.0.113.7:51234",
+			forwardedFor: []string{"198.51.100.1"},
+			expected:     "203.0.113.7",
+		},
+		{
+			name:           "trusted peer forwards the client",
+			trustedProxies: []string{"10.0.0.1"},
+			remoteAddr:     "10.0.0.1:51234",
+			forwardedFor:   []string{"198.51.100.1"},
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "spoofed hops left of the first untrusted hop are ignored",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:51234",
+			forwardedFor:   []string{"192.0.2.99, 198.51.100.1"},
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "multi-hop chain through trusted proxies",
+			trustedProxies: []string{"10.0.0.0/8", "172.16.0.5"},
+			remoteAddr:     "10.0.0.1:51234",
+			forwardedFor:   []string{"192.0.2.99, 198.51.100.1, 172.16.0.5", "10.1.2.3"},
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "chain of only trusted proxies uses the leftmost hop",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:51234",
+			forwardedFor:   []string{"10.0.0.3, 10.0.0.2"},
+			expected:       "10.0.0.3",
+		},
+		{
+			name:           "malformed hop stops at the last valid address",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:51234",
+			forwardedFor:   []string{"198.51.100.1, unknown, 10.0.0.2"},
+			expected:       "10.0.0.2",
+		},
+		{
+			name:           "trusted peer without X-Forwarded-For",
+			trustedProxies: []string{"10.0.0.1"},
+			remoteAddr:     "10.0.0.1:51234",
+			expected:       "10.0.0.1",
+		},
+		{
+			name:           "IPv4-mapped IPv6 addresses are unmapped",
+			trustedProxies: []string{"10.0.0.1"},
+			remoteAddr:     "[::ffff:10.0.0.1]:51234",
+			forwardedFor:   []string{"::ffff:198.51.100.1"},
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "IPv6 proxies",
+			trustedProxies: []string{"2001:db8::/32"},
+			remoteAddr:     "[2001:db8::1]:51234",
+			forwardedFor:   []string{"2001:db9::7"},
+			expected:       "2001:db9::7",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resolver, resolverError := NewClientIPResolver(testCase.trustedProxies)
+			if resolverError != nil {
+				t.Fatalf("NewClientIPResolver: %v", resolverError)
+			}
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = testCase.remoteAddr
+			for _, forwardedFor := range testCase.forwardedFor {
+				request.Header.Add("X-Forwarded-For", forwardedFor)
+			}
+
+			if clientIP := resolver.ClientIP(request); clientIP != testCase.expected {
+				t.Errorf("ClientIP = %q, want %q", clientIP, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolverRejectsInvalidProxies(t *testing.T) {
+	for _, trustedProxy := range []string{"10.0.0", "10.0.0.0/33", "proxy.internal"} {
+		t.Run(trustedProxy, func(t *testing.T) {
+			if _, resolverError := NewClientIPResolver([]string{trustedProxy}); resolverError == nil {
+				t.Errorf("NewClientIPResolver(%q) succeeded, want an error", trustedProxy)
+			}
+		})
+	}
+}
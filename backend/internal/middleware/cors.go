@@ -1,19 +1,247 @@
 package middleware
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
-func CORS(nextHandler http.Handler) http.Handler {
+// CORSPolicy decides which browser origins may call the API. AllowedOrigins
+// holds exact origins such as "https://portal.example.com", wildcard
+// subdomains such as "https://*.example.com" (which does not match the bare
+// domain), or "*" for any origin. "*" cannot be combined with
+// AllowCredentials.
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+var DefaultCORSPolicy = CORSPolicy{
+	AllowedOrigins: []string{"*"},
+	AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+	AllowedHeaders: []string{"Content-Type", "Authorization", "X-Admin-Token", "X-Participant-Token", "X-Request-ID", "If-None-Match"},
+	ExposedHeaders: []string{"ETag", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-Request-ID"},
+	MaxAge:         24 * time.Hour,
+}
+
+type originPattern struct {
+	// prefix is the scheme with "://" and suffix the domain (and port) a
+	// wildcard subdomain must end with. For exact origins only exact is set.
+	exact  string
+	prefix string
+	suffix string
+}
+
+func (p originPattern) matches(origin string) bool {
+	if p.exact != "" {
+		return origin == p.exact
+	}
+	if !strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+	subdomain := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+	return subdomain != "" && !strings.ContainsAny(subdomain, "/:@")
+}
+
+type corsPolicy struct {
+	allowsAnyOrigin  bool
+	originPatterns   []originPattern
+	allowedMethods   string
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+	methods          []string
+}
+
+func compileCORSPolicy(policy CORSPolicy) (*corsPolicy, error) {
+	compiledPolicy := &corsPolicy{
+		allowedMethods:   strings.Join(policy.AllowedMethods, ", "),
+		allowedHeaders:   strings.Join(policy.AllowedHeaders, ", "),
+		exposedHeaders:   strings.Join(policy.ExposedHeaders, ", "),
+		allowCredentials: policy.AllowCredentials,
+		maxAge:           strconv.Itoa(int(policy.MaxAge.Seconds())),
+		methods:          policy.AllowedMethods,
+	}
+
+	if len(policy.AllowedOrigins) == 0 {
+		return nil, fmt.Errorf("no allowed origins")
+	}
+
+	for _, allowedOrigin := range policy.AllowedOrigins {
+		if allowedOrigin == "*" {
+			compiledPolicy.allowsAnyOrigin = true
+			continue
+		}
+
+		pattern, parsingError := parseOriginPattern(allowedOrigin)
+		if parsingError != nil {
+			return nil, parsingError
+		}
+		compiledPolicy.originPatterns = append(compiledPolicy.originPatterns, pattern)
+	}
+
+	if compiledPolicy.allowsAnyOrigin && policy.AllowCredentials {
+		return nil, fmt.Errorf("credentials cannot be allowed for any origin, list the origins instead of *")
+	}
+
+	return compiledPolicy, nil
+}
+
+func parseOriginPattern(allowedOrigin string) (originPattern, error) {
+	normalizedOrigin := strings.ToLower(strings.TrimSuffix(allowedOrigin, "/"))
+
+	schemeText, hostText, hasScheme := strings.Cut(normalizedOrigin, "://")
+	if !hasScheme || (schemeText != "http" && schemeText != "https") {
+		return originPattern{}, fmt.Errorf("invalid origin %q, expected scheme://host[:port]", allowedOrigin)
+	}
+
+	isWildcard := strings.HasPrefix(hostText, "*.")
+	hostText = strings.TrimPrefix(hostText, "*.")
+
+	parsedOrigin, parsingError := url.Parse(schemeText + "://" + hostText)
+	if parsingError != nil || parsedOrigin.Host != hostText || strings.Contains(hostText, "*") {
+		return originPattern{}, fmt.Errorf("invalid origin %q, expected scheme://host[:port]", allowedOrigin)
+	}
+
+	if isWildcard {
+		return originPattern{prefix: schemeText + "://", suffix: "." + hostText}, nil
+	}
+	return originPattern{exact: normalizedOrigin}, nil
+}
+
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	if p.allowsAnyOrigin {
+		return true
+	}
+	normalizedOrigin := strings.ToLower(origin)
+	return slices.ContainsFunc(p.originPatterns, func(pattern originPattern) bool {
+		return pattern.matches(normalizedOrigin)
+	})
+}
+
+// CORS applies a default policy and per-route overrides. It runs outside the
+// application ServeMux, and preflight requests never match a route there, so
+// overrides are matched by path on a ServeMux of their own.
+type CORS struct {
+	defaultPolicy *corsPolicy
+	routes        *http.ServeMux
+	routePolicies map[string]*corsPolicy
+}
+
+func NewCORS(defaultPolicy CORSPolicy) (*CORS, error) {
+	compiledPolicy, compilationError := compileCORSPolicy(defaultPolicy)
+	if compilationError != nil {
+		return nil, fmt.Errorf("invalid CORS policy: %w", compilationError)
+	}
+
+	return &CORS{
+		defaultPolicy: compiledPolicy,
+		routes:        http.NewServeMux(),
+		routePolicies: map[string]*corsPolicy{},
+	}, nil
+}
+
+// Route uses policy for requests whose path matches pattern, a ServeMux
+// pattern without a method such as "/api/calendars/{calendar_id}/events".
+func (c *CORS) Route(pattern string, policy CORSPolicy) (registrationError error) {
+	if strings.ContainsAny(pattern, " \t") {
+		return fmt.Errorf("invalid CORS route %s, preflight requests use OPTIONS so the pattern must not name a method", pattern)
+	}
+
+	compiledPolicy, compilationError := compileCORSPolicy(policy)
+	if compilationError != nil {
+		return fmt.Errorf("invalid CORS policy for %s: %w", pattern, compilationError)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			registrationError = fmt.Errorf("invalid CORS route %s: %v", pattern, recovered)
+		}
+	}()
+	c.routes.Handle(pattern, http.NotFoundHandler())
+	c.routePolicies[pattern] = compiledPolicy
+
+	return nil
+}
+
+func (c *CORS) policyFor(r *http.Request) *corsPolicy {
+	if _, matchedPattern := c.routes.Handler(r); matchedPattern != "" {
+		if routePolicy, hasPolicy := c.routePolicies[matchedPattern]; hasPolicy {
+			return routePolicy
+		}
+	}
+	return c.defaultPolicy
+}
+
+// Handler answers preflight requests itself and refuses them with 403 when
+// the origin or method is not allowed. Other requests are always passed on;
+// for a disallowed origin they get no CORS headers and the browser withholds
+// the response.
+func (c *CORS) Handler(nextHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Max-Age", "86400")
+		policy := c.policyFor(r)
+		requestOrigin := r.Header.Get("Origin")
+
+		// A response that echoes the origin must not be served from a shared
+		// cache to a different origin.
+		if !policy.allowsAnyOrigin {
+			w.Header().Add("Vary", "Origin")
+		}
+
+		isPreflight := r.Method == http.MethodOptions && requestOrigin != "" && r.Header.Get("Access-Control-Request-Method") != ""
+		if isPreflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if requestOrigin == "" {
+			nextHandler.ServeHTTP(w, r)
+			return
+		}
+
+		if !policy.allowsOrigin(requestOrigin) {
+			if isPreflight {
+				respondError(w, http.StatusForbidden, "Origin not allowed")
+				return
+			}
+			nextHandler.ServeHTTP(w, r)
+			return
+		}
+
+		if isPreflight && !slices.Contains(policy.methods, r.Header.Get("Access-Control-Request-Method")) {
+			respondError(w, http.StatusForbidden, "Method not allowed")
+			return
+		}
+
+		if policy.allowsAnyOrigin {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", requestOrigin)
+		}
+		if policy.allowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
 
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
+		if !isPreflight {
+			if policy.exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", policy.exposedHeaders)
+			}
+			nextHandler.ServeHTTP(w, r)
 			return
 		}
 
-		nextHandler.ServeHTTP(w, r)
+		w.Header().Set("Access-Control-Allow-Methods", policy.allowedMethods)
+		w.Header().Set("Access-Control-Allow-Headers", policy.allowedHeaders)
+		w.Header().Set("Access-Control-Max-Age", policy.maxAge)
+		w.WriteHeader(http.StatusNoContent)
 	})
 }
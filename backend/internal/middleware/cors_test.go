@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCORSPolicyAllowsOrigin(t *testing.T) {
+	policy, compilationError := compileCORSPolicy(CORSPolicy{
+		AllowedOrigins: []string{"https://portal.example.com", "https://*.example.org", "http://*.localhost:5173"},
+	})
+	if compilationError != nil {
+		t.Fatalf("compileCORSPolicy: %v", compilationError)
+	}
+
+	testCases := []struct {
+		origin   string
+		expected bool
+	}{
+		{origin: "https://portal.example.com", expected: true},
+		{origin: "HTTPS://Portal.Example.com", expected: true},
+		{origin: "http://portal.example.com", expected: false},
+		{origin: "https://other.example.com", expected: false},
+		{origin: "https://app.example.org", expected: true},
+		{origin: "https://a.b.example.org", expected: true},
+		{origin: "https://example.org", expected: false},
+		{origin: "https://.example.org", expected: false},
+		{origin: "https://evil.com/.example.org", expected: false},
+		{origin: "https://user@app.example.org", expected: false},
+		{origin: "https://app.example.org.evil.com", expected: false},
+		{origin: "http://app.example.org", expected: false},
+		{origin: "http://app.localhost:5173", expected: true},
+		{origin: "http://app.localhost:5174", expected: false},
+		{origin: "http://evil.com:80.localhost:5173", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.origin, func(t *testing.T) {
+			if isAllowed := policy.allowsOrigin(testCase.origin); isAllowed != testCase.expected {
+				t.Errorf("allowsOrigin(%q) = %t, want %t", testCase.origin, isAllowed, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestNewCORSRejectsInvalidPolicies(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy CORSPolicy
+	}{
+		{name: "no origins", policy: CORSPolicy{}},
+		{name: "any origin with credentials", policy: CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}},
+		{name: "listed and any origin with credentials", policy: CORSPolicy{AllowedOrigins: []string{"https://example.com", "*"}, AllowCredentials: true}},
+		{name: "origin without a scheme", policy: CORSPolicy{AllowedOrigins: []string{"example.com"}}},
+		{name: "unsupported scheme", policy: CORSPolicy{AllowedOrigins: []string{"ftp://example.com"}}},
+		{name: "origin with a path", policy: CORSPolicy{AllowedOrigins: []string{"https://example.com/app"}}},
+		{name: "wildcard inside the host", policy: CORSPolicy{AllowedOrigins: []string{"https://app.*.example.com"}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, corsError := NewCORS(testCase.policy); corsError == nil {
+				t.Error("NewCORS succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestCORSRouteRejectsInvalidPatterns(t *testing.T) {
+	corsHandler, corsError := NewCORS(DefaultCORSPolicy)
+	if corsError != nil {
+		t.Fatalf("NewCORS: %v", corsError)
+	}
+
+	validPolicy := CORSPolicy{AllowedOrigins: []string{"https://example.com"}}
+	for _, pattern := range []string{"GET /api/calendars", "/api/{calendar_id"} {
+		if routeError := corsHandler.Route(pattern, validPolicy); routeError == nil {
+			t.Errorf("Route(%q) succeeded, want an error", pattern)
+		}
+	}
+	if routeError := corsHandler.Route("/api/calendars/{calendar_id}/events", CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}); routeError == nil {
+		t.Error("Route with * and credentials succeeded, want an error")
+	}
+}
+
+func TestCORSHandler(t *testing.T) {
+	defaultPolicy := DefaultCORSPolicy
+	defaultPolicy.AllowedOrigins = []string{"https://app.example.com"}
+
+	corsHandler, corsError := NewCORS(defaultPolicy)
+	if corsError != nil {
+		t.Fatalf("NewCORS: %v", corsError)
+	}
+
+	eventsPolicy := defaultPolicy
+	eventsPolicy.AllowedOrigins = []string{"https://*.partner.example"}
+	eventsPolicy.AllowCredentials = true
+	if routeError := corsHandler.Route("/api/calendars/{calendar_id}/events", eventsPolicy); routeError != nil {
+		t.Fatalf("Route: %v", routeError)
+	}
+
+	publicPolicy := defaultPolicy
+	publicPolicy.AllowedOrigins = []string{"*"}
+	if routeError := corsHandler.Route("/api/health", publicPolicy); routeError != nil {
+		t.Fatalf("Route: %v", routeError)
+	}
+
+	handler := corsHandler.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		name              string
+		method            string
+		path              string
+		origin            string
+		preflightMethod   string
+		expectedStatus    int
+		expectedHeaders   map[string]string
+		expectedVary      []string
+		expectedNoHeaders []string
+	}{
+		{
+			name:           "allowed origin",
+			method:         http.MethodGet,
+			path:           "/api/calendars/1",
+			origin:         "https://app.example.com",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "https://app.example.com",
+				"Access-Control-Expose-Headers": strings.Join(DefaultCORSPolicy.ExposedHeaders, ", "),
+			},
+			expectedVary:      []string{"Origin"},
+			expectedNoHeaders: []string{"Access-Control-Allow-Credentials", "Access-Control-Allow-Methods"},
+		},
+		{
+			name:              "disallowed origin is served without CORS headers",
+			method:            http.MethodGet,
+			path:              "/api/calendars/1",
+			origin:            "https://evil.example",
+			expectedStatus:    http.StatusOK,
+			expectedVary:      []string{"Origin"},
+			expectedNoHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:              "request without an origin",
+			method:            http.MethodGet,
+			path:              "/api/calendars/1",
+			expectedStatus:    http.StatusOK,
+			expectedVary:      []string{"Origin"},
+			expectedNoHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:            "preflight",
+			method:          http.MethodOptions,
+			path:            "/api/calendars/1",
+			origin:          "https://app.example.com",
+			preflightMethod: http.MethodPut,
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": strings.Join(DefaultCORSPolicy.AllowedMethods, ", "),
+				"Access-Control-Allow-Headers": strings.Join(DefaultCORSPolicy.AllowedHeaders, ", "),
+				"Access-Control-Max-Age":       "86400",
+			},
+			expectedVary:      []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			expectedNoHeaders: []string{"Access-Control-Expose-Headers"},
+		},
+		{
+			name:              "preflight from a disallowed origin",
+			method:            http.MethodOptions,
+			path:              "/api/calendars/1",
+			origin:            "https://evil.example",
+			preflightMethod:   http.MethodPost,
+			expectedStatus:    http.StatusForbidden,
+			expectedNoHeaders: []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods"},
+		},
+		{
+			name:              "preflight for a disallowed method",
+			method:            http.MethodOptions,
+			path:              "/api/calendars/1",
+			origin:            "https://app.example.com",
+			preflightMethod:   "PROPFIND",
+			expectedStatus:    http.StatusForbidden,
+			expectedNoHeaders: []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods"},
+		},
+		{
+			name:           "route override allows its wildcard origins with credentials",
+			method:         http.MethodGet,
+			path:           "/api/calendars/1/events",
+			origin:         "https://tenant.partner.example",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://tenant.partner.example",
+				"Access-Control-Allow-Credentials": "true",
+			},
+			expectedVary: []string{"Origin"},
+		},
+		{
+			name:              "route override replaces the default origins",
+			method:            http.MethodGet,
+			path:              "/api/calendars/1/events",
+			origin:            "https://app.example.com",
+			expectedStatus:    http.StatusOK,
+			expectedNoHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:            "route override applies to preflight requests",
+			method:          http.MethodOptions,
+			path:            "/api/calendars/1/events",
+			origin:          "https://tenant.partner.example",
+			preflightMethod: http.MethodGet,
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://tenant.partner.example",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name:           "any origin does not vary by origin",
+			method:         http.MethodGet,
+			path:           "/api/health",
+			origin:         "https://anyone.example",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "*",
+			},
+			expectedNoHeaders: []string{"Vary", "Access-Control-Allow-Credentials"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			if testCase.origin != "" {
+				request.Header.Set("Origin", testCase.origin)
+			}
+			if testCase.preflightMethod != "" {
+				request.Header.Set("Access-Control-Request-Method", testCase.preflightMethod)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, testCase.expectedStatus)
+			}
+			for headerName, expectedValue := range testCase.expectedHeaders {
+				if headerValue := recorder.Header().Get(headerName); headerValue != expectedValue {
+					t.Errorf("%s = %q, want %q", headerName, headerValue, expectedValue)
+				}
+			}
+			for _, headerName := range testCase.expectedNoHeaders {
+				if headerValue, isSet := recorder.Header()[headerName]; isSet {
+					t.Errorf("%s = %q, want it unset", headerName, headerValue)
+				}
+			}
+			if testCase.expectedVary != nil && !slices.Equal(recorder.Header().Values("Vary"), testCase.expectedVary) {
+				t.Errorf("Vary = %v, want %v", recorder.Header().Values("Vary"), testCase.expectedVary)
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -23,18 +23,29 @@ func (w *responseWriter) Write(dataBytes []byte) (int, error) {
 	return bytesWritten, writeError
 }
 
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func Logging(nextHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 		wrappedWriter := &responseWriter{ResponseWriter: w, status: http.StatusOK}
 
 		nextHandler.ServeHTTP(wrappedWriter, r)
-		log.Printf("%s %s %d %dB %s",
-			r.Method,
-			r.URL.Path,
-			wrappedWriter.status,
-			wrappedWriter.size,
-			time.Since(startTime),
+
+		logLevel := slog.LevelInfo
+		if wrappedWriter.status >= http.StatusInternalServerError {
+			logLevel = slog.LevelError
+		}
+
+		slog.Log(r.Context(), logLevel, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", r.Pattern,
+			"status", wrappedWriter.status,
+			"bytes", wrappedWriter.size,
+			"duration_ms", time.Since(startTime).Milliseconds(),
 		)
 	})
 }
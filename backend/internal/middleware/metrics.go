@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"meeting-planner/backend/internal/metrics"
+	"net/http"
+	"time"
+)
+
+// Metrics records request counts and latency per route pattern. It reads
+// r.Pattern after the request has been served, which only works when the
+// ServeMux receives the same *http.Request, so a middleware between this
+// one and the mux that replaces the request has to copy the pattern back, as
+// Tracing does.
+func Metrics(nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		wrappedWriter := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		nextHandler.ServeHTTP(wrappedWriter, r)
+
+		metrics.ObserveHTTPRequest(r.Method, r.Pattern, wrappedWriter.status, time.Since(startTime))
+	})
+}
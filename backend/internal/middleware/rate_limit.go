@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"meeting-planner/backend/internal/metrics"
+	"meeting-planner/backend/internal/ratelimit"
+	"net/http"
+	"strconv"
+)
+
+type RateLimiter struct {
+	store     ratelimit.Store
+	clientIPs *ClientIPResolver
+	// allowOnStoreError lets requests through while the store fails instead
+	// of answering 503, trading protection for availability.
+	allowOnStoreError bool
+}
+
+func NewRateLimiter(store ratelimit.Store, clientIPs *ClientIPResolver, allowOnStoreError bool) *RateLimiter {
+	return &RateLimiter{
+		store:             store,
+		clientIPs:         clientIPs,
+		allowOnStoreError: allowOnStoreError,
+	}
+}
+
+// Limit wraps a single route registered on an http.ServeMux. Buckets are
+// keyed by the matched route pattern and the client IP, so each route keeps
+// its own budget. When the store fails the request is let through or
+// answered with 503, depending on allowOnStoreError; either way the failure
+// is logged and counted.
+func (l *RateLimiter) Limit(limit ratelimit.Limit, nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucketKey := r.Pattern + "|" + l.clientIPs.ClientIP(r)
+
+		decision, takingError := l.store.Take(r.Context(), bucketKey, limit)
+		if takingError != nil {
+			if l.allowOnStoreError {
+				metrics.RateLimitStoreErrors.WithLabelValues("allowed").Inc()
+				slog.ErrorContext(r.Context(), "rate limit check failed, allowing request", "error", takingError)
+				nextHandler.ServeHTTP(w, r)
+				return
+			}
+			metrics.RateLimitStoreErrors.WithLabelValues("denied").Inc()
+			slog.ErrorContext(r.Context(), "rate limit check failed, denying request", "error", takingError)
+			respondError(w, http.StatusServiceUnavailable, "Service temporarily unavailable, try again later")
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+
+		if !decision.Allowed {
+			retryAfterSeconds := max(int(math.Ceil(decision.RetryAfter.Seconds())), 1)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+			respondError(w, http.StatusTooManyRequests, "Too many requests, try again later")
+			return
+		}
+
+		nextHandler.ServeHTTP(w, r)
+	})
+}
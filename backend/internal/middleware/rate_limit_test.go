@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"meeting-planner/backend/internal/metrics"
+	"meeting-planner/backend/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fixedStore answers every Take with the same decision or error and keeps
+// the keys it was asked for.
+type fixedStore struct {
+	decision ratelimit.Decision
+	err      error
+	keys     []string
+}
+
+func (s *fixedStore) Take(_ context.Context, key string, _ ratelimit.Limit) (ratelimit.Decision, error) {
+	s.keys = append(s.keys, key)
+	return s.decision, s.err
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 5, Period: time.Minute}
+	storeError := errors.New("store unavailable")
+
+	testCases := []struct {
+		name               string
+		decision           ratelimit.Decision
+		storeError         error
+		allowOnStoreError  bool
+		expectedStatus     int
+		expectedHeaders    map[string]string
+		expectedErrorCount map[string]float64
+	}{
+		{
+			name:           "allowed request",
+			decision:       ratelimit.Decision{Allowed: true, Remaining: 4},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"X-RateLimit-Limit":     "5",
+				"X-RateLimit-Remaining": "4",
+				"Retry-After":           "",
+			},
+		},
+		{
+			name:           "refused request waits whole seconds",
+			decision:       ratelimit.Decision{Allowed: false, RetryAfter: 1500 * time.Millisecond},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				"X-RateLimit-Limit":     "5",
+				"X-RateLimit-Remaining": "0",
+				"Retry-After":           "2",
+			},
+		},
+		{
+			name:           "refused request waits at least a second",
+			decision:       ratelimit.Decision{Allowed: false, RetryAfter: 10 * time.Millisecond},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				"Retry-After": "1",
+			},
+		},
+		{
+			name:               "store error allows the request when configured to",
+			storeError:         storeError,
+			allowOnStoreError:  true,
+			expectedStatus:     http.StatusNoContent,
+			expectedHeaders:    map[string]string{"X-RateLimit-Limit": ""},
+			expectedErrorCount: map[string]float64{"allowed": 1, "denied": 0},
+		},
+		{
+			name:               "store error denies the request otherwise",
+			storeError:         storeError,
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedHeaders:    map[string]string{"X-RateLimit-Limit": ""},
+			expectedErrorCount: map[string]float64{"allowed": 0, "denied": 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			metrics.RateLimitStoreErrors.Reset()
+
+			store := &fixedStore{decision: testCase.decision, err: testCase.storeError}
+			clientIPs, resolverError := NewClientIPResolver(nil)
+			if resolverError != nil {
+				t.Fatalf("NewClientIPResolver: %v", resolverError)
+			}
+			rateLimiter := NewRateLimiter(store, clientIPs, testCase.allowOnStoreError)
+
+			routeMux := http.NewServeMux()
+			routeMux.Handle("POST /api/calendars", rateLimiter.Limit(limit, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})))
+
+			request := httptest.NewRequest(http.MethodPost, "/api/calendars", nil)
+			request.RemoteAddr = "203.0.113.7:51234"
+			recorder := httptest.NewRecorder()
+			routeMux.ServeHTTP(recorder, request)
+
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, testCase.expectedStatus)
+			}
+			for headerName, expectedValue := range testCase.expectedHeaders {
+				if headerValue := recorder.Header().Get(headerName); headerValue != expectedValue {
+					t.Errorf("%s = %q, want %q", headerName, headerValue, expectedValue)
+				}
+			}
+			if len(store.keys) != 1 || store.keys[0] != "POST /api/calendars|203.0.113.7" {
+				t.Errorf("bucket keys = %v, want [POST /api/calendars|203.0.113.7]", store.keys)
+			}
+			for outcome, expectedCount := range testCase.expectedErrorCount {
+				if count := testutil.ToFloat64(metrics.RateLimitStoreErrors.WithLabelValues(outcome)); count != expectedCount {
+					t.Errorf("%s store errors = %v, want %v", outcome, count, expectedCount)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package middleware
 
 import (
-	"log"
+	"encoding/json"
+	"log/slog"
 	"net/http"
 	"runtime/debug"
 )
@@ -10,11 +11,27 @@ func Recovery(nextHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		defer func() {
 			if panicError := recover(); panicError != nil {
-				log.Printf("Panic: %v\n%s", panicError, debug.Stack())
-				http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
+				slog.ErrorContext(request.Context(), "panic while handling request",
+					"panic", panicError,
+					"stack", string(debug.Stack()),
+				)
+				respondError(writer, http.StatusInternalServerError, "Internal Server Error")
 			}
 		}()
 
 		nextHandler.ServeHTTP(writer, request)
 	})
 }
+
+// respondError mirrors handlers.RespondError for the few responses written
+// before a request reaches a handler.
+func respondError(w http.ResponseWriter, status int, message string) {
+	responseBody := map[string]any{"error": message}
+	if requestID := w.Header().Get(RequestIDHeader); requestID != "" {
+		responseBody["request_id"] = requestID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(responseBody)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"meeting-planner/backend/internal/logging"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// RequestID reuses the X-Request-ID sent by a proxy or client when it looks
+// sane and generates one otherwise. The ID is stored in the request context
+// for logging and echoed in the response.
+func RequestID(nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = utils.UUIDToString(utils.NewUUID())
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		nextHandler.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, character := range requestID {
+		isAllowed := character >= 'a' && character <= 'z' ||
+			character >= 'A' && character <= 'Z' ||
+			character >= '0' && character <= '9' ||
+			character == '-' || character == '_' || character == '.' || character == ':'
+		if !isAllowed {
+			return false
+		}
+	}
+
+	return true
+}
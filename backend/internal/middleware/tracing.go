@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("meeting-planner/backend/internal/middleware")
+
+// Tracing starts a server span for every request, continuing the trace from
+// an incoming traceparent header. It is meant to wrap the ServeMux directly.
+func Tracing(nextHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parentContext := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		spanContext, span := tracer.Start(parentContext, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		wrappedWriter := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		tracedRequest := r.WithContext(spanContext)
+
+		nextHandler.ServeHTTP(wrappedWriter, tracedRequest)
+
+		// The mux stores the matched pattern on the request it was given.
+		// Copy it back so that Logging and Metrics further out still see it.
+		r.Pattern = tracedRequest.Pattern
+
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+			span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(wrappedWriter.status))
+		if wrappedWriter.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(wrappedWriter.status))
+		}
+	})
+}
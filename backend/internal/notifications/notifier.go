@@ -0,0 +1,163 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sendTimeout = 30 * time.Second
+
+// Notifier turns calendar activity into emails for the organizer. Messages
+// are sent in the background so that a slow mail server never delays an API
+// response.
+type Notifier struct {
+	sender    Sender
+	publicURL string
+	pending   sync.WaitGroup
+}
+
+func NewNotifier(sender Sender, publicURL string) *Notifier {
+	return &Notifier{
+		sender:    sender,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Close waits for the messages that are still being sent.
+func (n *Notifier) Close() {
+	n.pending.Wait()
+}
+
+func (n *Notifier) send(message Message) {
+	n.pending.Add(1)
+	go func() {
+		defer n.pending.Done()
+
+		sendingContext, cancelSending := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancelSending()
+
+		if sendingError := n.sender.Send(sendingContext, message); sendingError != nil {
+			slog.Error("failed to send email",
+				"to", message.To,
+				"subject", message.Subject,
+				"error", sendingError,
+			)
+		}
+	}()
+}
+
+func (n *Notifier) calendarURL(calendarID string) string {
+	return n.publicURL + "/calendars/" + url.PathEscape(calendarID)
+}
+
+type CalendarCreatedNotice struct {
+	To         string
+	CalendarID string
+	Title      string
+	AdminToken string
+}
+
+// CalendarCreated sends the organizer the admin link. The token goes in the
+// fragment so it never reaches server or proxy logs.
+func (n *Notifier) CalendarCreated(notice CalendarCreatedNotice) {
+	calendarURL := n.calendarURL(notice.CalendarID)
+	adminURL := calendarURL + "#admin_token=" + url.QueryEscape(notice.AdminToken)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Your calendar %q is ready.\n\n", notice.Title)
+	fmt.Fprintf(&body, "Share this link with participants:\n%s\n\n", calendarURL)
+	fmt.Fprintf(&body, "Keep this admin link to edit or close the calendar later. Anyone who has it can manage the calendar:\n%s\n", adminURL)
+
+	n.send(Message{
+		To:      notice.To,
+		Subject: fmt.Sprintf("Your calendar %q has been created", notice.Title),
+		Body:    body.String(),
+	})
+}
+
+type ResponseReceivedNotice struct {
+	To              string
+	CalendarID      string
+	Title           string
+	ParticipantName string
+}
+
+func (n *Notifier) ResponseReceived(notice ResponseReceivedNotice) {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s has answered your calendar %q.\n\n", notice.ParticipantName, notice.Title)
+	fmt.Fprintf(&body, "See all responses:\n%s\n", n.calendarURL(notice.CalendarID))
+
+	n.send(Message{
+		To:      notice.To,
+		Subject: fmt.Sprintf("New response to %q", notice.Title),
+		Body:    body.String(),
+	})
+}
+
+type TimeSlotSummary struct {
+	StartDate     time.Time
+	EndDate       time.Time
+	YesCount      int
+	IfNeedBeCount int
+	NoCount       int
+}
+
+type DeadlineSummaryNotice struct {
+	To           string
+	CalendarID   string
+	Title        string
+	Location     *time.Location
+	Participants []string
+	// TimeSlots holds the best ranked slots, best first.
+	TimeSlots []TimeSlotSummary
+}
+
+func (n *Notifier) DeadlineSummary(notice DeadlineSummaryNotice) {
+	location := notice.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "The deadline for your calendar %q has passed.\n\n", notice.Title)
+
+	if len(notice.Participants) == 0 {
+		body.WriteString("Nobody responded before the deadline.\n")
+	} else {
+		fmt.Fprintf(&body, "%d people responded: %s.\n", len(notice.Participants), strings.Join(notice.Participants, ", "))
+	}
+
+	if len(notice.TimeSlots) > 0 {
+		body.WriteString("\nTop time slots:\n")
+		for index, slot := range notice.TimeSlots {
+			fmt.Fprintf(&body, "%d. %s: %d yes, %d if need be, %d no\n",
+				index+1,
+				formatTimeRange(slot.StartDate.In(location), slot.EndDate.In(location)),
+				slot.YesCount,
+				slot.IfNeedBeCount,
+				slot.NoCount,
+			)
+		}
+	}
+
+	fmt.Fprintf(&body, "\nSee the full results:\n%s\n", n.calendarURL(notice.CalendarID))
+
+	n.send(Message{
+		To:      notice.To,
+		Subject: fmt.Sprintf("Responses to %q are closed", notice.Title),
+		Body:    body.String(),
+	})
+}
+
+func formatTimeRange(startDate time.Time, endDate time.Time) string {
+	start := startDate.Format("Mon 2 Jan 2006 15:04")
+	if startDate.YearDay() == endDate.YearDay() && startDate.Year() == endDate.Year() {
+		return start + " - " + endDate.Format("15:04 MST")
+	}
+	return start + " - " + endDate.Format("Mon 2 Jan 2006 15:04 MST")
+}
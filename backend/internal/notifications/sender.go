@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers a single message. Implementations must be safe for
+// concurrent use.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// LogSender drops messages, logging only their recipient and subject. It is
+// used when no mail server is configured. Bodies are never logged because
+// they carry secrets such as the organizer's admin link.
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, message Message) error {
+	slog.InfoContext(ctx, "email not sent, delivery is disabled", "to", message.To, "subject", message.Subject)
+	return nil
+}
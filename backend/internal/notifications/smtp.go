@@ -0,0 +1,174 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SMTPSecurity string
+
+const (
+	// SMTPSecurityStartTLS upgrades the connection with STARTTLS and refuses
+	// to send when the server does not offer it.
+	SMTPSecurityStartTLS SMTPSecurity = "starttls"
+	// SMTPSecurityTLS connects over TLS from the start, usually on port 465.
+	SMTPSecurityTLS SMTPSecurity = "tls"
+	// SMTPSecurityNone sends in plain text. Only use it for a local relay or
+	// a fake server during development.
+	SMTPSecurityNone SMTPSecurity = "none"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Security SMTPSecurity
+}
+
+type SMTPSender struct {
+	config      SMTPConfig
+	fromAddress *mail.Address
+}
+
+func NewSMTPSender(config SMTPConfig) (*SMTPSender, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if config.Port <= 0 {
+		return nil, errors.New("smtp port must be positive")
+	}
+	if config.Security == "" {
+		config.Security = SMTPSecurityStartTLS
+	}
+	switch config.Security {
+	case SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp security mode %q", config.Security)
+	}
+
+	fromAddress, parsingError := mail.ParseAddress(config.From)
+	if parsingError != nil {
+		return nil, fmt.Errorf("invalid smtp from address: %w", parsingError)
+	}
+
+	return &SMTPSender{
+		config:      config,
+		fromAddress: fromAddress,
+	}, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	toAddress, parsingError := mail.ParseAddress(message.To)
+	if parsingError != nil {
+		return fmt.Errorf("invalid recipient address: %w", parsingError)
+	}
+
+	connection, dialError := s.dial(ctx)
+	if dialError != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", dialError)
+	}
+
+	// net/smtp has no context support, so cancellation closes the connection
+	// and the deadline bounds every read and write.
+	stopClosing := context.AfterFunc(ctx, func() {
+		connection.Close()
+	})
+	defer stopClosing()
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		_ = connection.SetDeadline(deadline)
+	}
+
+	client, clientError := smtp.NewClient(connection, s.config.Host)
+	if clientError != nil {
+		connection.Close()
+		return fmt.Errorf("failed to start smtp session: %w", clientError)
+	}
+	defer client.Close()
+
+	if s.config.Security == SMTPSecurityStartTLS {
+		if supportsStartTLS, _ := client.Extension("STARTTLS"); !supportsStartTLS {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if startTLSError := client.StartTLS(&tls.Config{ServerName: s.config.Host}); startTLSError != nil {
+			return fmt.Errorf("failed to start tls: %w", startTLSError)
+		}
+	}
+
+	if s.config.Username != "" {
+		authentication := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if authenticationError := client.Auth(authentication); authenticationError != nil {
+			return fmt.Errorf("failed to authenticate with smtp server: %w", authenticationError)
+		}
+	}
+
+	if mailError := client.Mail(s.fromAddress.Address); mailError != nil {
+		return fmt.Errorf("smtp server rejected sender: %w", mailError)
+	}
+	if recipientError := client.Rcpt(toAddress.Address); recipientError != nil {
+		return fmt.Errorf("smtp server rejected recipient: %w", recipientError)
+	}
+
+	dataWriter, dataError := client.Data()
+	if dataError != nil {
+		return fmt.Errorf("failed to start smtp data: %w", dataError)
+	}
+	if _, writeError := dataWriter.Write(s.encode(toAddress, message, time.Now())); writeError != nil {
+		return fmt.Errorf("failed to write smtp data: %w", writeError)
+	}
+	if closeError := dataWriter.Close(); closeError != nil {
+		return fmt.Errorf("smtp server rejected message: %w", closeError)
+	}
+
+	return client.Quit()
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	if s.config.Security == SMTPSecurityTLS {
+		tlsDialer := &tls.Dialer{
+			NetDialer: dialer,
+			Config:    &tls.Config{ServerName: s.config.Host},
+		}
+		return tlsDialer.DialContext(ctx, "tcp", address)
+	}
+
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+func (s *SMTPSender) encode(toAddress *mail.Address, message Message, now time.Time) []byte {
+	var encodedMessage bytes.Buffer
+
+	senderDomain := s.fromAddress.Address[strings.LastIndex(s.fromAddress.Address, "@")+1:]
+
+	fmt.Fprintf(&encodedMessage, "From: %s\r\n", s.fromAddress.String())
+	fmt.Fprintf(&encodedMessage, "To: %s\r\n", toAddress.String())
+	fmt.Fprintf(&encodedMessage, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&encodedMessage, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&encodedMessage, "Message-ID: <%s@%s>\r\n", rand.Text(), senderDomain)
+	encodedMessage.WriteString("MIME-Version: 1.0\r\n")
+	encodedMessage.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	encodedMessage.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	encodedMessage.WriteString("\r\n")
+
+	bodyWriter := quotedprintable.NewWriter(&encodedMessage)
+	_, _ = bodyWriter.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n")))
+	_ = bodyWriter.Close()
+
+	return encodedMessage.Bytes()
+}
@@ -0,0 +1,265 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// receivedMail is what the fake server saw during one SMTP session.
+type receivedMail struct {
+	from       string
+	recipients []string
+	data       []byte
+}
+
+// fakeSMTPServer speaks just enough plain-text SMTP for net/smtp to deliver
+// one message per connection. It does not offer STARTTLS.
+type fakeSMTPServer struct {
+	listener net.Listener
+	received chan receivedMail
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, listenError := net.Listen("tcp", "127.0.0.1:0")
+	if listenError != nil {
+		t.Fatalf("failed to listen: %v", listenError)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{listener: listener, received: make(chan receivedMail, 1)}
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		connection, acceptError := s.listener.Accept()
+		if acceptError != nil {
+			return
+		}
+		go s.handle(connection)
+	}
+}
+
+func (s *fakeSMTPServer) handle(connection net.Conn) {
+	defer connection.Close()
+
+	session := textproto.NewConn(connection)
+	var currentMail receivedMail
+
+	_ = session.PrintfLine("220 fake.test ESMTP")
+	for {
+		line, readError := session.ReadLine()
+		if readError != nil {
+			return
+		}
+		command, argument, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			_ = session.PrintfLine("250-fake.test")
+			_ = session.PrintfLine("250 HELP")
+		case "MAIL":
+			currentMail.from = argument
+			_ = session.PrintfLine("250 OK")
+		case "RCPT":
+			currentMail.recipients = append(currentMail.recipients, argument)
+			_ = session.PrintfLine("250 OK")
+		case "DATA":
+			_ = session.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, dataError := session.ReadDotBytes()
+			if dataError != nil {
+				return
+			}
+			currentMail.data = data
+			_ = session.PrintfLine("250 OK")
+			s.received <- currentMail
+		case "QUIT":
+			_ = session.PrintfLine("221 Bye")
+			return
+		default:
+			_ = session.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) config(security SMTPSecurity) SMTPConfig {
+	address := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{
+		Host:     address.IP.String(),
+		Port:     address.Port,
+		From:     "Meeting Planner <noreply@example.com>",
+		Security: security,
+	}
+}
+
+func TestSMTPSenderSendsMessage(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	sender, senderError := NewSMTPSender(server.config(SMTPSecurityNone))
+	if senderError != nil {
+		t.Fatalf("NewSMTPSender: %v", senderError)
+	}
+
+	message := Message{
+		To:      "Zoë Organizer <organizer@example.com>",
+		Subject: "Spotkanie zespołu – podsumowanie",
+		Body: "Cześć,\n" +
+			".a line starting with a dot\n" +
+			"Manage the calendar: https://planner.example.com/calendars/00000000-0000-0000-0000-000000000000#admin_token=abc=def\n" +
+			strings.Repeat("long line ", 20),
+	}
+
+	sendContext, cancelSend := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelSend()
+	if sendingError := sender.Send(sendContext, message); sendingError != nil {
+		t.Fatalf("Send: %v", sendingError)
+	}
+
+	var received receivedMail
+	select {
+	case received = <-server.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake server received no message")
+	}
+
+	if received.from != "FROM:<noreply@example.com>" {
+		t.Errorf("MAIL %s, want FROM:<noreply@example.com>", received.from)
+	}
+	if len(received.recipients) != 1 || received.recipients[0] != "TO:<organizer@example.com>" {
+		t.Errorf("RCPT %v, want [TO:<organizer@example.com>]", received.recipients)
+	}
+
+	parsedMessage, parsingError := mail.ReadMessage(strings.NewReader(string(received.data)))
+	if parsingError != nil {
+		t.Fatalf("message is not RFC 5322: %v", parsingError)
+	}
+
+	decodedSubject, decodingError := new(mime.WordDecoder).DecodeHeader(parsedMessage.Header.Get("Subject"))
+	if decodingError != nil || decodedSubject != message.Subject {
+		t.Errorf("Subject = %q (%v), want %q", decodedSubject, decodingError, message.Subject)
+	}
+
+	fromAddress, fromError := parsedMessage.Header.AddressList("From")
+	if fromError != nil || len(fromAddress) != 1 || fromAddress[0].Address != "noreply@example.com" || fromAddress[0].Name != "Meeting Planner" {
+		t.Errorf("From = %q", parsedMessage.Header.Get("From"))
+	}
+	toAddress, toError := parsedMessage.Header.AddressList("To")
+	if toError != nil || len(toAddress) != 1 || toAddress[0].Address != "organizer@example.com" || toAddress[0].Name != "Zoë Organizer" {
+		t.Errorf("To = %q", parsedMessage.Header.Get("To"))
+	}
+
+	expectedHeaders := map[string]string{
+		"Mime-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "quoted-printable",
+	}
+	for headerName, expectedValue := range expectedHeaders {
+		if headerValue := parsedMessage.Header.Get(headerName); headerValue != expectedValue {
+			t.Errorf("%s = %q, want %q", headerName, headerValue, expectedValue)
+		}
+	}
+	if messageID := parsedMessage.Header.Get("Message-Id"); !strings.HasSuffix(messageID, "@example.com>") {
+		t.Errorf("Message-ID = %q, want it on the sender domain", messageID)
+	}
+	if _, dateError := parsedMessage.Header.Date(); dateError != nil {
+		t.Errorf("Date header: %v", dateError)
+	}
+
+	// ReadDotBytes has already turned the CRLF line endings into LF.
+	rawBody, _ := io.ReadAll(parsedMessage.Body)
+	for rawLine := range strings.SplitSeq(string(rawBody), "\n") {
+		if len(rawLine) > 76 {
+			t.Errorf("encoded body line is %d characters long, want at most 76", len(rawLine))
+		}
+	}
+
+	decodedBody, bodyError := io.ReadAll(quotedprintable.NewReader(strings.NewReader(string(rawBody))))
+	if bodyError != nil {
+		t.Fatalf("body is not quoted-printable: %v", bodyError)
+	}
+	if strings.TrimSuffix(string(decodedBody), "\n") != message.Body {
+		t.Errorf("decoded body = %q, want %q", decodedBody, message.Body)
+	}
+}
+
+func TestSMTPSenderRequiresStartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	sender, senderError := NewSMTPSender(server.config(SMTPSecurityStartTLS))
+	if senderError != nil {
+		t.Fatalf("NewSMTPSender: %v", senderError)
+	}
+
+	sendingError := sender.Send(context.Background(), Message{To: "organizer@example.com", Subject: "Hi", Body: "Hi"})
+	if sendingError == nil || !strings.Contains(sendingError.Error(), "STARTTLS") {
+		t.Errorf("Send = %v, want a missing STARTTLS error", sendingError)
+	}
+
+	select {
+	case <-server.received:
+		t.Error("message was sent without STARTTLS")
+	default:
+	}
+}
+
+func TestSMTPSenderDeadlineAbortsStalledServer(t *testing.T) {
+	listener, listenError := net.Listen("tcp", "127.0.0.1:0")
+	if listenError != nil {
+		t.Fatalf("failed to listen: %v", listenError)
+	}
+	defer listener.Close()
+
+	// The server accepts connections but never sends its greeting.
+	go func() {
+		for {
+			connection, acceptError := listener.Accept()
+			if acceptError != nil {
+				return
+			}
+			defer connection.Close()
+		}
+	}()
+
+	_, portText, _ := net.SplitHostPort(listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+	sender, senderError := NewSMTPSender(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     port,
+		From:     "noreply@example.com",
+		Security: SMTPSecurityNone,
+	})
+	if senderError != nil {
+		t.Fatalf("NewSMTPSender: %v", senderError)
+	}
+
+	sendContext, cancelSend := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelSend()
+
+	startTime := time.Now()
+	sendingError := sender.Send(sendContext, Message{To: "organizer@example.com", Subject: "Hi", Body: "Hi"})
+	elapsed := time.Since(startTime)
+
+	if sendingError == nil {
+		t.Fatal("Send succeeded against a stalled server")
+	}
+	var networkError net.Error
+	if !errors.As(sendingError, &networkError) && !errors.Is(sendingError, net.ErrClosed) {
+		t.Errorf("Send = %v, want a network timeout or closed connection", sendingError)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Send returned after %v, want it bounded by the 200ms deadline", elapsed)
+	}
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// takeStep advances the clock by elapsed and then takes one token from the
+// bucket of key.
+type takeStep struct {
+	key      string
+	elapsed  time.Duration
+	expected Decision
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	// Two tokens every four seconds keeps the refill arithmetic exact.
+	limit := Limit{Requests: 2, Period: 4 * time.Second}
+	startTime := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		steps []takeStep
+	}{
+		{
+			name: "burst is spent and then refused with the time to the next token",
+			steps: []takeStep{
+				{key: "a", expected: Decision{Allowed: true, Remaining: 1}},
+				{key: "a", expected: Decision{Allowed: true, Remaining: 0}},
+				{key: "a", expected: Decision{Allowed: false, Remaining: 0, RetryAfter: 2 * time.Second}},
+				{key: "a", elapsed: time.Second, expected: Decision{Allowed: false, Remaining: 0, RetryAfter: time.Second}},
+			},
+		},
+		{
+			name: "tokens refill over the period",
+			steps: []takeStep{
+				{key: "a", expected: Decision{Allowed: true, Remaining: 1}},
+				{key: "a", expected: Decision{Allowed: true, Remaining: 0}},
+				{key: "a", elapsed: 2 * time.Second, expected: Decision{Allowed: true, Remaining: 0}},
+				{key: "a", elapsed: 2 * time.Second, expected: Decision{Allowed: true, Remaining: 0}},
+			},
+		},
+		{
+			name: "a bucket never holds more than the burst",
+			steps: []takeStep{
+				{key: "a", expected: Decision{Allowed: true, Remaining: 1}},
+				{key: "a", elapsed: time.Hour, expected: Decision{Allowed: true, Remaining: 1}},
+				{key: "a", expected: Decision{Allowed: true, Remaining: 0}},
+				{key: "a", expected: Decision{Allowed: false, Remaining: 0, RetryAfter: 2 * time.Second}},
+			},
+		},
+		{
+			name: "keys have separate buckets",
+			steps: []takeStep{
+				{key: "a", expected: Decision{Allowed: true, Remaining: 1}},
+				{key: "a", expected: Decision{Allowed: true, Remaining: 0}},
+				{key: "b", expected: Decision{Allowed: true, Remaining: 1}},
+				{key: "a", expected: Decision{Allowed: false, Remaining: 0, RetryAfter: 2 * time.Second}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			currentTime := startTime
+			store := NewMemoryStore()
+			store.now = func() time.Time { return currentTime }
+
+			for stepIndex, step := range testCase.steps {
+				currentTime = currentTime.Add(step.elapsed)
+				decision, takingError := store.Take(context.Background(), step.key, limit)
+				if takingError != nil {
+					t.Fatalf("step %d: Take: %v", stepIndex, takingError)
+				}
+				if decision != step.expected {
+					t.Errorf("step %d: decision = %+v, want %+v", stepIndex, decision, step.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	// One token per sweep interval, so a bucket missing one token is full
+	// again by the next sweep and one missing two is not.
+	limit := Limit{Requests: 2, Period: 2 * memorySweepInterval}
+	currentTime := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return currentTime }
+	store.lastSweep = currentTime
+
+	for _, key := range []string{"refilled", "spent", "spent"} {
+		if _, takingError := store.Take(context.Background(), key, limit); takingError != nil {
+			t.Fatalf("Take: %v", takingError)
+		}
+	}
+
+	currentTime = currentTime.Add(memorySweepInterval + time.Second)
+	if _, takingError := store.Take(context.Background(), "other", limit); takingError != nil {
+		t.Fatalf("Take: %v", takingError)
+	}
+
+	if _, exists := store.buckets["refilled"]; exists {
+		t.Error("the refilled bucket was not swept")
+	}
+	if _, exists := store.buckets["spent"]; !exists {
+		t.Error("the spent bucket was swept before it refilled")
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"meeting-planner/backend/internal/db/sqlc"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const postgresPruneInterval = 10 * time.Minute
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so that every
+// replica sees the same limits. Each Take is a single upsert, which keeps
+// concurrent requests for the same key consistent.
+type PostgresStore struct {
+	queries *sqlc.Queries
+	// idleTTL is how long an untouched bucket is kept. It must be at least
+	// the longest limit period, otherwise buckets reset early.
+	idleTTL time.Duration
+
+	mutex     sync.Mutex
+	lastPrune time.Time
+}
+
+func NewPostgresStore(queries *sqlc.Queries, idleTTL time.Duration) *PostgresStore {
+	return &PostgresStore{
+		queries:   queries,
+		idleTTL:   idleTTL,
+		lastPrune: time.Now(),
+	}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	s.pruneIfDue()
+
+	bucket, takingError := s.queries.TakeRateLimitToken(ctx, sqlc.TakeRateLimitTokenParams{
+		Key:        key,
+		Burst:      float64(limit.Requests),
+		RefillRate: limit.refillRate(),
+	})
+	if takingError != nil {
+		return Decision{}, fmt.Errorf("failed to take rate limit token: %w", takingError)
+	}
+
+	decision := Decision{
+		Allowed:   bucket.Allowed,
+		Remaining: int(bucket.Tokens),
+	}
+	if !decision.Allowed {
+		decision.RetryAfter = limit.retryAfter(bucket.Tokens)
+	}
+
+	return decision, nil
+}
+
+func (s *PostgresStore) pruneIfDue() {
+	s.mutex.Lock()
+	if time.Since(s.lastPrune) < postgresPruneInterval {
+		s.mutex.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	s.mutex.Unlock()
+
+	go func() {
+		pruneContext, cancelPrune := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancelPrune()
+
+		cutoff := pgtype.Timestamptz{Time: time.Now().Add(-s.idleTTL), Valid: true}
+		if pruneError := s.queries.DeleteStaleRateLimitBuckets(pruneContext, cutoff); pruneError != nil {
+			slog.Error("failed to prune rate limit buckets", "error", pruneError)
+		}
+	}()
+}
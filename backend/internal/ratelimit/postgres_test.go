@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"meeting-planner/backend/internal/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// bucketTable stands in for the database and answers the TakeRateLimitToken
+// upsert with a fixed row.
+type bucketTable struct {
+	row       sqlc.TakeRateLimitTokenRow
+	err       error
+	arguments []any
+}
+
+func (b *bucketTable) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not implemented")
+}
+
+func (b *bucketTable) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (b *bucketTable) QueryRow(_ context.Context, _ string, arguments ...any) pgx.Row {
+	b.arguments = arguments
+	return bucketRow{table: b}
+}
+
+func (b *bucketTable) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+type bucketRow struct {
+	table *bucketTable
+}
+
+func (r bucketRow) Scan(destinations ...any) error {
+	if r.table.err != nil {
+		return r.table.err
+	}
+	*destinations[0].(*bool) = r.table.row.Allowed
+	*destinations[1].(*float64) = r.table.row.Tokens
+	return nil
+}
+
+func TestPostgresStoreTake(t *testing.T) {
+	limit := Limit{Requests: 10, Period: 5 * time.Second}
+
+	testCases := []struct {
+		name     string
+		row      sqlc.TakeRateLimitTokenRow
+		expected Decision
+	}{
+		{
+			name:     "allowed",
+			row:      sqlc.TakeRateLimitTokenRow{Allowed: true, Tokens: 8.5},
+			expected: Decision{Allowed: true, Remaining: 8},
+		},
+		{
+			name:     "refused",
+			row:      sqlc.TakeRateLimitTokenRow{Allowed: false, Tokens: 0.5},
+			expected: Decision{Allowed: false, Remaining: 0, RetryAfter: 250 * time.Millisecond},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			table := &bucketTable{row: testCase.row}
+			store := NewPostgresStore(sqlc.New(table), time.Hour)
+
+			decision, takingError := store.Take(context.Background(), "route|203.0.113.7", limit)
+			if takingError != nil {
+				t.Fatalf("Take: %v", takingError)
+			}
+			if decision != testCase.expected {
+				t.Errorf("decision = %+v, want %+v", decision, testCase.expected)
+			}
+
+			expectedArguments := []any{"route|203.0.113.7", 10.0, 2.0}
+			if len(table.arguments) != len(expectedArguments) {
+				t.Fatalf("arguments = %v, want %v", table.arguments, expectedArguments)
+			}
+			for argumentIndex := range expectedArguments {
+				if table.arguments[argumentIndex] != expectedArguments[argumentIndex] {
+					t.Errorf("argument %d = %v, want %v", argumentIndex, table.arguments[argumentIndex], expectedArguments[argumentIndex])
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresStoreTakeReturnsQueryErrors(t *testing.T) {
+	queryError := errors.New("connection refused")
+	store := NewPostgresStore(sqlc.New(&bucketTable{err: queryError}), time.Hour)
+
+	if _, takingError := store.Take(context.Background(), "key", Limit{Requests: 1, Period: time.Second}); !errors.Is(takingError, queryError) {
+		t.Errorf("Take = %v, want %v", takingError, queryError)
+	}
+}
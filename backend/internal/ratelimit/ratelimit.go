@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket that holds Requests tokens and refills all
+// of them over Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit reads limits written as "<requests>/<period>", for example
+// "10/1m" or "100/1h".
+func ParseLimit(value string) (Limit, error) {
+	requestsText, periodText, hasSeparator := strings.Cut(strings.TrimSpace(value), "/")
+	if !hasSeparator {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<period>", value)
+	}
+
+	requests, requestsError := strconv.Atoi(requestsText)
+	if requestsError != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, requests must be a positive integer", value)
+	}
+
+	period, periodError := time.ParseDuration(periodText)
+	if periodError != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, period must be a positive duration", value)
+	}
+
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText lets limits be written as "<requests>/<period>" in config
+// files and environment variables.
+func (l *Limit) UnmarshalText(text []byte) error {
+	parsedLimit, parsingError := ParseLimit(string(text))
+	if parsingError != nil {
+		return parsingError
+	}
+	*l = parsedLimit
+	return nil
+}
+
+// refillRate is the number of tokens added to the bucket per second.
+func (l Limit) refillRate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// retryAfter is how long a bucket holding tokens needs to refill one token.
+func (l Limit) retryAfter(tokens float64) time.Duration {
+	missingTokens := math.Max(1-tokens, 0)
+	return time.Duration(math.Ceil(missingTokens / l.refillRate() * float64(time.Second)))
+}
+
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. Take removes one token from the bucket named by
+// key, creating a full bucket on first use.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
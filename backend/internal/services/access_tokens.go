@@ -0,0 +1,87 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"meeting-planner/backend/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	accessTokenTTL = 1 * time.Hour
+	// feedTokenTTL is long because calendar apps keep polling a subscribed
+	// feed with the URL they were given and cannot exchange a password.
+	feedTokenTTL = 365 * 24 * time.Hour
+)
+
+// accessTokenIssuer signs tokens for one scope, so that a token issued for
+// the iCalendar feed is not accepted as an access token and the other way
+// round.
+type accessTokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+	scope  string
+}
+
+// issue signs the token together with the calendar's password hash, which
+// is not part of the token itself. Changing or removing the password changes
+// the hash, so every token issued before that stops verifying.
+func (i accessTokenIssuer) issue(calendarID pgtype.UUID, passwordHash string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(i.ttl).Truncate(time.Second)
+	payload := fmt.Sprintf("%s:%d", utils.UUIDToString(calendarID), expiresAt.Unix())
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	encodedSignature := base64.RawURLEncoding.EncodeToString(i.sign(payload, passwordHash))
+
+	return encodedPayload + "." + encodedSignature, expiresAt
+}
+
+func (i accessTokenIssuer) verify(token string, calendarID pgtype.UUID, passwordHash string, now time.Time) bool {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return false
+	}
+
+	payloadBytes, payloadError := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if payloadError != nil {
+		return false
+	}
+
+	signature, signatureError := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if signatureError != nil {
+		return false
+	}
+
+	payload := string(payloadBytes)
+	if !hmac.Equal(signature, i.sign(payload, passwordHash)) {
+		return false
+	}
+
+	tokenCalendarID, expiresAtString, found := strings.Cut(payload, ":")
+	if !found || tokenCalendarID != utils.UUIDToString(calendarID) {
+		return false
+	}
+
+	expiresAtUnix, parsingError := strconv.ParseInt(expiresAtString, 10, 64)
+	if parsingError != nil {
+		return false
+	}
+
+	return now.Before(time.Unix(expiresAtUnix, 0))
+}
+
+func (i accessTokenIssuer) sign(payload string, passwordHash string) []byte {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(i.scope))
+	mac.Write([]byte{0})
+	mac.Write([]byte(payload))
+	mac.Write([]byte{0})
+	mac.Write([]byte(passwordHash))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestAccessTokenIssuerVerify(t *testing.T) {
+	issuer := accessTokenIssuer{secret: []byte("test secret"), ttl: time.Hour, scope: "access"}
+	feedIssuer := accessTokenIssuer{secret: []byte("test secret"), ttl: time.Hour, scope: "feed"}
+	calendarID := testUUID(0xca, 1)
+	issuedAt := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	token, _ := issuer.issue(calendarID, "password hash", issuedAt)
+	feedToken, _ := feedIssuer.issue(calendarID, "password hash", issuedAt)
+
+	testCases := []struct {
+		name         string
+		token        string
+		calendarID   pgtype.UUID
+		passwordHash string
+		now          time.Time
+		expected     bool
+	}{
+		{name: "valid token", token: token, calendarID: calendarID, passwordHash: "password hash", now: issuedAt.Add(time.Minute), expected: true},
+		{name: "expired token", token: token, calendarID: calendarID, passwordHash: "password hash", now: issuedAt.Add(time.Hour), expected: false},
+		{name: "other calendar", token: token, calendarID: testUUID(0xca, 2), passwordHash: "password hash", now: issuedAt, expected: false},
+		{name: "changed password", token: token, calendarID: calendarID, passwordHash: "new password hash", now: issuedAt, expected: false},
+		{name: "removed password", token: token, calendarID: calendarID, passwordHash: "", now: issuedAt, expected: false},
+		{name: "tampered token", token: token + "x", calendarID: calendarID, passwordHash: "password hash", now: issuedAt, expected: false},
+		{name: "token of another scope", token: feedToken, calendarID: calendarID, passwordHash: "password hash", now: issuedAt, expected: false},
+		{name: "malformed token", token: "not a token", calendarID: calendarID, passwordHash: "password hash", now: issuedAt, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if isValid := issuer.verify(testCase.token, testCase.calendarID, testCase.passwordHash, testCase.now); isValid != testCase.expected {
+				t.Errorf("verify = %t, want %t", isValid, testCase.expected)
+			}
+		})
+	}
+}
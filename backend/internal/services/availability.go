@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"meeting-planner/backend/internal/ical"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (s *CalendarService) SuggestTimeSlotsFromICalendar(ctx context.Context, calendarID pgtype.UUID, reader io.Reader) ([]pgtype.UUID, error) {
+	ctx, span := startSpan(ctx, "SuggestTimeSlotsFromICalendar", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return nil, calendarError
+	}
+
+	location, locationError := time.LoadLocation(calendar.TimeZone)
+	if locationError != nil {
+		location = time.UTC
+	}
+
+	timeSlots, timeSlotsError := s.queries.GetCalendarTimeSlotsByCalendarID(ctx, calendarID)
+	if timeSlotsError != nil {
+		return nil, fmt.Errorf("failed to get calendar time slots: %w", timeSlotsError)
+	}
+
+	// Recurring events only need to be expanded over the time slots.
+	var window ical.Interval
+	for _, slot := range timeSlots {
+		if window.Start.IsZero() || slot.StartDate.Time.Before(window.Start) {
+			window.Start = slot.StartDate.Time
+		}
+		if slot.EndDate.Time.After(window.End) {
+			window.End = slot.EndDate.Time
+		}
+	}
+
+	busyIntervals, parsingError := ical.ParseBusyIntervals(reader, location, window)
+	if parsingError != nil {
+		return nil, parsingError
+	}
+
+	freeSlotIDs := []pgtype.UUID{}
+	for _, slot := range timeSlots {
+		if !overlapsAny(busyIntervals, slot.StartDate.Time, slot.EndDate.Time) {
+			freeSlotIDs = append(freeSlotIDs, slot.ID)
+		}
+	}
+
+	return freeSlotIDs, nil
+}
+
+func overlapsAny(intervals []ical.Interval, start time.Time, end time.Time) bool {
+	for _, interval := range intervals {
+		if interval.Overlaps(start, end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meeting-planner/backend/internal/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type CalendarCredentials struct {
+	AccessToken string
+	AdminToken  string
+	// FeedToken is only accepted by AuthorizeCalendarFeed.
+	FeedToken string
+}
+
+func (s *CalendarService) AuthorizeCalendarAccess(ctx context.Context, calendarID pgtype.UUID, credentials CalendarCredentials) error {
+	ctx, span := startSpan(ctx, "AuthorizeCalendarAccess", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return calendarError
+	}
+
+	isAdmin := credentials.AdminToken != "" && calendar.AdminTokenHash != nil && utils.TokenMatchesHash(credentials.AdminToken, *calendar.AdminTokenHash)
+
+	if CalendarStatus(calendar.Status) == CalendarStatusDraft && !isAdmin {
+		return ErrCalendarNotFound
+	}
+
+	if calendar.PasswordHash == nil || isAdmin {
+		return nil
+	}
+
+	if credentials.AccessToken == "" {
+		return ErrPasswordRequired
+	}
+	if !s.accessTokens.verify(credentials.AccessToken, calendarID, *calendar.PasswordHash, time.Now()) {
+		return ErrInvalidAccessToken
+	}
+
+	return nil
+}
+
+// AuthorizeCalendarFeed also accepts the long-lived feed token, which only
+// grants reading the iCalendar feed so that calendar apps can stay
+// subscribed to a password protected calendar.
+func (s *CalendarService) AuthorizeCalendarFeed(ctx context.Context, calendarID pgtype.UUID, credentials CalendarCredentials) error {
+	if credentials.FeedToken == "" {
+		return s.AuthorizeCalendarAccess(ctx, calendarID, credentials)
+	}
+
+	ctx, span := startSpan(ctx, "AuthorizeCalendarFeed", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return calendarError
+	}
+
+	if CalendarStatus(calendar.Status) == CalendarStatusDraft {
+		return ErrCalendarNotFound
+	}
+
+	if calendar.PasswordHash == nil {
+		return nil
+	}
+	if !s.feedTokens.verify(credentials.FeedToken, calendarID, *calendar.PasswordHash, time.Now()) {
+		return ErrInvalidAccessToken
+	}
+
+	return nil
+}
+
+func (s *CalendarService) AuthorizeCalendarAdmin(ctx context.Context, calendarID pgtype.UUID, adminToken string) error {
+	ctx, span := startSpan(ctx, "AuthorizeCalendarAdmin", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return calendarError
+	}
+
+	if adminToken == "" {
+		return ErrAdminTokenRequired
+	}
+
+	if calendar.AdminTokenHash == nil || !utils.TokenMatchesHash(adminToken, *calendar.AdminTokenHash) {
+		return ErrInvalidAdminToken
+	}
+
+	return nil
+}
+
+type AccessToken struct {
+	Token     string
+	ExpiresAt time.Time
+	// FeedToken reads the iCalendar feed for much longer than Token lasts.
+	FeedToken          string
+	FeedTokenExpiresAt time.Time
+}
+
+func (s *CalendarService) ExchangePassword(ctx context.Context, calendarID pgtype.UUID, password string) (AccessToken, error) {
+	ctx, span := startSpan(ctx, "ExchangePassword", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return AccessToken{}, calendarError
+	}
+
+	if calendar.PasswordHash == nil {
+		return AccessToken{}, ErrPasswordNotSet
+	}
+	if comparisonError := comparePassword(*calendar.PasswordHash, password); comparisonError != nil {
+		return AccessToken{}, comparisonError
+	}
+
+	now := time.Now()
+	token, expiresAt := s.accessTokens.issue(calendarID, *calendar.PasswordHash, now)
+	feedToken, feedTokenExpiresAt := s.feedTokens.issue(calendarID, *calendar.PasswordHash, now)
+
+	return AccessToken{
+		Token:              token,
+		ExpiresAt:          expiresAt,
+		FeedToken:          feedToken,
+		FeedTokenExpiresAt: feedTokenExpiresAt,
+	}, nil
+}
+
+func hashPassword(password string) (string, error) {
+	passwordHash, hashingError := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if hashingError != nil {
+		return "", fmt.Errorf("failed to hash calendar password: %w", hashingError)
+	}
+	return string(passwordHash), nil
+}
+
+func comparePassword(passwordHash string, password string) error {
+	comparisonError := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
+	if comparisonError == nil {
+		return nil
+	}
+	if errors.Is(comparisonError, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrInvalidPassword
+	}
+	return fmt.Errorf("failed to compare calendar password: %w", comparisonError)
+}
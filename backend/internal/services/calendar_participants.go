@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/notifications"
+	"meeting-planner/backend/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func getParticipant(ctx context.Context, queries *sqlc.Queries, calendarID pgtype.UUID, participantID pgtype.UUID) (sqlc.Participant, error) {
+	participant, participantError := queries.GetParticipantByID(ctx, participantID)
+	if participantError != nil {
+		if errors.Is(participantError, pgx.ErrNoRows) {
+			return sqlc.Participant{}, ErrParticipantNotFound
+		}
+		return sqlc.Participant{}, fmt.Errorf("failed to get participant: %w", participantError)
+	}
+
+	if participant.CalendarID != calendarID {
+		return sqlc.Participant{}, ErrParticipantNotFound
+	}
+
+	return participant, nil
+}
+
+type SubmitResponseInput struct {
+	CalendarID  pgtype.UUID
+	DisplayName string
+	Votes       []VoteInput
+}
+
+type SubmittedResponse struct {
+	ParticipantID pgtype.UUID
+	EditToken     string
+}
+
+func (s *CalendarService) SubmitResponse(ctx context.Context, input SubmitResponseInput) (SubmittedResponse, error) {
+	ctx, span := startSpan(ctx, "SubmitResponse", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	editToken, tokenError := utils.GenerateToken()
+	if tokenError != nil {
+		return SubmittedResponse{}, fmt.Errorf("failed to generate edit token: %w", tokenError)
+	}
+	editTokenHash := utils.HashToken(editToken)
+
+	submittedResponse := SubmittedResponse{
+		EditToken: editToken,
+	}
+
+	var calendar sqlc.Calendar
+	var claimedLegacyParticipant bool
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		var calendarError error
+		calendar, calendarError = getCalendar(ctx, queries, input.CalendarID)
+		if calendarError != nil {
+			return calendarError
+		}
+
+		if responsesError := ensureAcceptsResponses(calendar); responsesError != nil {
+			return responsesError
+		}
+
+		// Participants carried over from username based votes have no edit
+		// token. Submitting under a username used to replace its votes, so the
+		// first response under their name takes them over.
+		participantID, claimError := queries.ClaimLegacyParticipant(ctx, sqlc.ClaimLegacyParticipantParams{
+			CalendarID:    calendar.ID,
+			DisplayName:   input.DisplayName,
+			EditTokenHash: &editTokenHash,
+		})
+		switch {
+		case claimError == nil:
+			claimedLegacyParticipant = true
+		case errors.Is(claimError, pgx.ErrNoRows):
+			var creationError error
+			participantID, creationError = queries.CreateParticipant(ctx, sqlc.CreateParticipantParams{
+				CalendarID:    calendar.ID,
+				DisplayName:   input.DisplayName,
+				EditTokenHash: &editTokenHash,
+			})
+			if creationError != nil {
+				if isUniqueViolation(creationError, "idx_participants_calendar_display_name") {
+					return ErrParticipantNameTaken
+				}
+				return fmt.Errorf("failed to create participant: %w", creationError)
+			}
+		default:
+			return fmt.Errorf("failed to claim participant: %w", claimError)
+		}
+		submittedResponse.ParticipantID = participantID
+
+		return replaceParticipantVotes(ctx, queries, calendar.ID, participantID, input.Votes)
+	})
+	if transactionError != nil {
+		return SubmittedResponse{}, transactionError
+	}
+
+	countVotes(input.Votes)
+
+	eventType := events.TypeVoteCreated
+	if claimedLegacyParticipant {
+		eventType = events.TypeVoteChanged
+	}
+	s.publish(input.CalendarID, eventType, map[string]any{
+		"participant_id": utils.UUIDToString(submittedResponse.ParticipantID),
+		"username":       input.DisplayName,
+	})
+
+	if s.notifier != nil && calendar.OrganizerEmail != nil {
+		s.notifier.ResponseReceived(notifications.ResponseReceivedNotice{
+			To:              *calendar.OrganizerEmail,
+			CalendarID:      utils.UUIDToString(calendar.ID),
+			Title:           calendar.Title,
+			ParticipantName: input.DisplayName,
+		})
+	}
+
+	return submittedResponse, nil
+}
+
+func (s *CalendarService) AuthorizeParticipant(ctx context.Context, calendarID pgtype.UUID, participantID pgtype.UUID, editToken string) error {
+	ctx, span := startSpan(ctx, "AuthorizeParticipant", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	participant, participantError := getParticipant(ctx, s.queries, calendarID, participantID)
+	if participantError != nil {
+		return participantError
+	}
+
+	if editToken == "" {
+		return ErrEditTokenRequired
+	}
+
+	if participant.EditTokenHash == nil || !utils.TokenMatchesHash(editToken, *participant.EditTokenHash) {
+		return ErrInvalidEditToken
+	}
+
+	return nil
+}
+
+type UpdateResponseInput struct {
+	CalendarID    pgtype.UUID
+	ParticipantID pgtype.UUID
+	DisplayName   *string
+	// Votes replaces every vote of the participant. A nil slice keeps the
+	// current votes, an empty one clears them.
+	Votes []VoteInput
+}
+
+func (s *CalendarService) UpdateResponse(ctx context.Context, input UpdateResponseInput) error {
+	ctx, span := startSpan(ctx, "UpdateResponse", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	var displayName string
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		calendar, calendarError := getCalendar(ctx, queries, input.CalendarID)
+		if calendarError != nil {
+			return calendarError
+		}
+
+		if responsesError := ensureAcceptsResponses(calendar); responsesError != nil {
+			return responsesError
+		}
+
+		participant, participantError := getParticipant(ctx, queries, calendar.ID, input.ParticipantID)
+		if participantError != nil {
+			return participantError
+		}
+		displayName = participant.DisplayName
+
+		if input.DisplayName != nil && *input.DisplayName != participant.DisplayName {
+			updateError := queries.UpdateParticipantDisplayName(ctx, sqlc.UpdateParticipantDisplayNameParams{
+				ID:          participant.ID,
+				DisplayName: *input.DisplayName,
+			})
+			if updateError != nil {
+				if isUniqueViolation(updateError, "idx_participants_calendar_display_name") {
+					return ErrParticipantNameTaken
+				}
+				return fmt.Errorf("failed to update participant: %w", updateError)
+			}
+			displayName = *input.DisplayName
+		}
+
+		if input.Votes == nil {
+			return nil
+		}
+
+		return replaceParticipantVotes(ctx, queries, calendar.ID, participant.ID, input.Votes)
+	})
+	if transactionError != nil {
+		return transactionError
+	}
+
+	countVotes(input.Votes)
+	s.publish(input.CalendarID, events.TypeVoteChanged, map[string]any{
+		"participant_id": utils.UUIDToString(input.ParticipantID),
+		"username":       displayName,
+	})
+	return nil
+}
+
+func (s *CalendarService) WithdrawResponse(ctx context.Context, calendarID pgtype.UUID, participantID pgtype.UUID) error {
+	ctx, span := startSpan(ctx, "WithdrawResponse", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	var displayName string
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		calendar, calendarError := getCalendar(ctx, queries, calendarID)
+		if calendarError != nil {
+			return calendarError
+		}
+
+		if responsesError := ensureAcceptsResponses(calendar); responsesError != nil {
+			return responsesError
+		}
+
+		participant, participantError := getParticipant(ctx, queries, calendarID, participantID)
+		if participantError != nil {
+			return participantError
+		}
+		displayName = participant.DisplayName
+
+		if deletionError := queries.DeleteParticipantByID(ctx, participantID); deletionError != nil {
+			return fmt.Errorf("failed to delete participant: %w", deletionError)
+		}
+
+		return nil
+	})
+	if transactionError != nil {
+		return transactionError
+	}
+
+	s.publish(calendarID, events.TypeVoteWithdrawn, map[string]any{
+		"participant_id": utils.UUIDToString(participantID),
+		"username":       displayName,
+	})
+	return nil
+}
@@ -2,21 +2,131 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"meeting-planner/backend/internal/db"
 	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/metrics"
+	"meeting-planner/backend/internal/notifications"
+	"meeting-planner/backend/internal/utils"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const uniqueViolationCode = "23505"
+
+var (
+	ErrCalendarNotFound        = errors.New("calendar not found")
+	ErrTimeSlotNotInCalendar   = errors.New("time slot does not belong to calendar")
+	ErrVoteConflict            = errors.New("vote conflicts with an existing vote")
+	ErrPasswordRequired        = errors.New("calendar password required")
+	ErrInvalidPassword         = errors.New("invalid calendar password")
+	ErrPasswordNotSet          = errors.New("calendar is not password protected")
+	ErrInvalidAccessToken      = errors.New("invalid or expired access token")
+	ErrAdminTokenRequired      = errors.New("admin token required")
+	ErrInvalidAdminToken       = errors.New("invalid admin token")
+	ErrTimeSlotNotFound        = errors.New("time slot not found")
+	ErrResponsesClosed         = errors.New("calendar no longer accepts responses")
+	ErrInvalidTimeSlot         = errors.New("invalid time slot")
+	ErrInvalidTimeZone         = errors.New("invalid time zone")
+	ErrInvalidVotePreference   = errors.New("invalid vote preference")
+	ErrInvalidStatusTransition = errors.New("invalid calendar status transition")
+	ErrFinalTimeSlotRequired   = errors.New("final time slot required to finalize calendar")
+	ErrCalendarLocked          = errors.New("calendar time slots can no longer be changed")
+	ErrParticipantNotFound     = errors.New("participant not found")
+	ErrParticipantNameTaken    = errors.New("participant name already taken")
+	ErrEditTokenRequired       = errors.New("participant edit token required")
+	ErrInvalidEditToken        = errors.New("invalid participant edit token")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+)
+
+const defaultTimeZone = "UTC"
+
 type CalendarService struct {
-	queries *sqlc.Queries
+	database     *db.DB
+	queries      *sqlc.Queries
+	accessTokens accessTokenIssuer
+	feedTokens   accessTokenIssuer
+	events       *events.Hub
+	notifier     *notifications.Notifier
 }
 
-func NewCalendarService(queries *sqlc.Queries) *CalendarService {
+func NewCalendarService(database *db.DB, accessTokenSecret []byte, eventHub *events.Hub, notifier *notifications.Notifier) *CalendarService {
 	return &CalendarService{
-		queries: queries,
+		database: database,
+		queries:  database.Queries,
+		accessTokens: accessTokenIssuer{
+			secret: accessTokenSecret,
+			ttl:    accessTokenTTL,
+			scope:  "access",
+		},
+		feedTokens: accessTokenIssuer{
+			secret: accessTokenSecret,
+			ttl:    feedTokenTTL,
+			scope:  "feed",
+		},
+		events:   eventHub,
+		notifier: notifier,
+	}
+}
+
+func (s *CalendarService) publish(calendarID pgtype.UUID, eventType string, data map[string]any) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(calendarID, eventType, data)
+}
+
+func (s *CalendarService) withTx(ctx context.Context, transactionFunc func(queries *sqlc.Queries) error) error {
+	transaction, beginError := s.database.Pool.Begin(ctx)
+	if beginError != nil {
+		return fmt.Errorf("failed to begin transaction: %w", beginError)
+	}
+	defer transaction.Rollback(ctx)
+
+	if transactionError := transactionFunc(s.queries.WithTx(transaction)); transactionError != nil {
+		return transactionError
+	}
+
+	if commitError := transaction.Commit(ctx); commitError != nil {
+		return fmt.Errorf("failed to commit transaction: %w", commitError)
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error, constraintName string) bool {
+	var postgresError *pgconn.PgError
+	if !errors.As(err, &postgresError) {
+		return false
+	}
+	return postgresError.Code == uniqueViolationCode && postgresError.ConstraintName == constraintName
+}
+
+func getCalendar(ctx context.Context, queries *sqlc.Queries, calendarID pgtype.UUID) (sqlc.Calendar, error) {
+	calendar, calendarError := queries.GetCalendarByID(ctx, calendarID)
+	if calendarError != nil {
+		if errors.Is(calendarError, pgx.ErrNoRows) {
+			return sqlc.Calendar{}, ErrCalendarNotFound
+		}
+		return sqlc.Calendar{}, fmt.Errorf("failed to get calendar: %w", calendarError)
 	}
+	return calendar, nil
+}
+
+func acceptsResponses(calendar sqlc.Calendar, now time.Time) bool {
+	return !calendar.AcceptResponsesUntil.Valid || now.Before(calendar.AcceptResponsesUntil.Time)
+}
+
+func toTimestamptz(value *time.Time) pgtype.Timestamptz {
+	if value == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *value, Valid: true}
 }
 
 type CreateCalendarInput struct {
@@ -24,59 +134,340 @@ type CreateCalendarInput struct {
 	Description          *string
 	Location             *string
 	AcceptResponsesUntil *time.Time
+	Password             *string
+	TimeZone             string
+	Status               CalendarStatus
+	OrganizerEmail       *string
+	TimeSlots            []TimeSlotInput
+}
+
+type CreatedCalendar struct {
+	ID          pgtype.UUID
+	AdminToken  string
+	TimeSlotIDs []pgtype.UUID
 }
 
-func (s *CalendarService) CreateCalendar(ctx context.Context, input CreateCalendarInput) (pgtype.UUID, error) {
+func (s *CalendarService) CreateCalendar(ctx context.Context, input CreateCalendarInput) (CreatedCalendar, error) {
+	ctx, span := startSpan(ctx, "CreateCalendar")
+	defer span.End()
+
 	queryParams := sqlc.CreateCalendarParams{
-		Title:       input.Title,
-		Description: input.Description,
-		Location:    input.Location,
+		Title:                input.Title,
+		Description:          input.Description,
+		Location:             input.Location,
+		AcceptResponsesUntil: toTimestamptz(input.AcceptResponsesUntil),
+		TimeZone:             input.TimeZone,
+		Status:               string(input.Status),
+		OrganizerEmail:       emptyToNil(input.OrganizerEmail),
+	}
+
+	if queryParams.Status == "" {
+		queryParams.Status = string(CalendarStatusOpen)
+	}
+	if input.Status != "" && input.Status != CalendarStatusDraft && input.Status != CalendarStatusOpen {
+		return CreatedCalendar{}, fmt.Errorf("%w: a new calendar must be draft or open", ErrInvalidStatusTransition)
 	}
 
-	if input.AcceptResponsesUntil != nil {
-		queryParams.AcceptResponsesUntil = pgtype.Timestamptz{
-			Time:  *input.AcceptResponsesUntil,
-			Valid: true,
+	if queryParams.TimeZone == "" {
+		queryParams.TimeZone = defaultTimeZone
+	}
+	if _, locationError := time.LoadLocation(queryParams.TimeZone); locationError != nil {
+		return CreatedCalendar{}, ErrInvalidTimeZone
+	}
+
+	if input.Password != nil {
+		passwordHash, hashingError := hashPassword(*input.Password)
+		if hashingError != nil {
+			return CreatedCalendar{}, hashingError
 		}
+		queryParams.PasswordHash = &passwordHash
 	}
 
-	calendarID, creationError := s.queries.CreateCalendar(ctx, queryParams)
-	if creationError != nil {
-		return pgtype.UUID{}, fmt.Errorf("failed to create calendar: %w", creationError)
+	adminToken, tokenError := utils.GenerateToken()
+	if tokenError != nil {
+		return CreatedCalendar{}, fmt.Errorf("failed to generate admin token: %w", tokenError)
 	}
+	adminTokenHash := utils.HashToken(adminToken)
+	queryParams.AdminTokenHash = &adminTokenHash
 
-	return calendarID, nil
-}
+	createdCalendar := CreatedCalendar{
+		AdminToken:  adminToken,
+		TimeSlotIDs: []pgtype.UUID{},
+	}
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		calendarID, creationError := queries.CreateCalendar(ctx, queryParams)
+		if creationError != nil {
+			return fmt.Errorf("failed to create calendar: %w", creationError)
+		}
+		createdCalendar.ID = calendarID
 
-type TimeSlotInput struct {
-	StartDate time.Time
-	EndDate   time.Time
+		if len(input.TimeSlots) == 0 {
+			return nil
+		}
+
+		calendar := sqlc.Calendar{
+			ID:       calendarID,
+			TimeZone: queryParams.TimeZone,
+		}
+
+		timeSlotIDs, timeSlotsError := createTimeSlots(ctx, queries, calendar, input.TimeSlots)
+		if timeSlotsError != nil {
+			return timeSlotsError
+		}
+		createdCalendar.TimeSlotIDs = timeSlotIDs
+
+		return nil
+	})
+	if transactionError != nil {
+		return CreatedCalendar{}, transactionError
+	}
+
+	metrics.CalendarsCreated.Inc()
+	metrics.TimeSlotsCreated.Add(float64(len(createdCalendar.TimeSlotIDs)))
+
+	if s.notifier != nil && queryParams.OrganizerEmail != nil {
+		s.notifier.CalendarCreated(notifications.CalendarCreatedNotice{
+			To:         *queryParams.OrganizerEmail,
+			CalendarID: utils.UUIDToString(createdCalendar.ID),
+			Title:      queryParams.Title,
+			AdminToken: adminToken,
+		})
+	}
+
+	return createdCalendar, nil
 }
 
-type CreateCalendarTimeSlotsInput struct {
-	CalendarID pgtype.UUID
-	TimeSlots  []TimeSlotInput
+type UpdateCalendarInput struct {
+	CalendarID                pgtype.UUID
+	Title                     *string
+	Description               *string
+	Location                  *string
+	AcceptResponsesUntil      *time.Time
+	ClearAcceptResponsesUntil bool
+	Password                  *string
+	TimeZone                  *string
+	OrganizerEmail            *string
 }
 
-func (s *CalendarService) CreateCalendarTimeSlots(ctx context.Context, input CreateCalendarTimeSlotsInput) error {
-	for _, slot := range input.TimeSlots {
-		queryParams := sqlc.CreateCalendarTimeSlotParams{
-			CalendarID: input.CalendarID,
-			StartDate: pgtype.Timestamptz{
-				Time:  slot.StartDate,
-				Valid: true,
-			},
-			EndDate: pgtype.Timestamptz{
-				Time:  slot.EndDate,
-				Valid: true,
-			},
+func (s *CalendarService) UpdateCalendar(ctx context.Context, input UpdateCalendarInput) error {
+	ctx, span := startSpan(ctx, "UpdateCalendar", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		calendar, calendarError := getCalendar(ctx, queries, input.CalendarID)
+		if calendarError != nil {
+			return calendarError
 		}
 
-		_, creationError := s.queries.CreateCalendarTimeSlot(ctx, queryParams)
-		if creationError != nil {
-			return fmt.Errorf("failed to create calendar time slot: %w", creationError)
+		queryParams := sqlc.UpdateCalendarParams{
+			ID:                   calendar.ID,
+			Title:                calendar.Title,
+			Description:          calendar.Description,
+			Location:             calendar.Location,
+			AcceptResponsesUntil: calendar.AcceptResponsesUntil,
+			PasswordHash:         calendar.PasswordHash,
+			TimeZone:             calendar.TimeZone,
+			OrganizerEmail:       calendar.OrganizerEmail,
+		}
+
+		if input.Title != nil {
+			queryParams.Title = *input.Title
+		}
+		if input.Description != nil {
+			queryParams.Description = emptyToNil(input.Description)
+		}
+		if input.Location != nil {
+			queryParams.Location = emptyToNil(input.Location)
 		}
+		if input.ClearAcceptResponsesUntil {
+			queryParams.AcceptResponsesUntil = pgtype.Timestamptz{}
+		} else if input.AcceptResponsesUntil != nil {
+			queryParams.AcceptResponsesUntil = toTimestamptz(input.AcceptResponsesUntil)
+		}
+		if input.Password != nil {
+			queryParams.PasswordHash = nil
+			if *input.Password != "" {
+				passwordHash, hashingError := hashPassword(*input.Password)
+				if hashingError != nil {
+					return hashingError
+				}
+				queryParams.PasswordHash = &passwordHash
+			}
+		}
+
+		if input.OrganizerEmail != nil {
+			queryParams.OrganizerEmail = emptyToNil(input.OrganizerEmail)
+		}
+
+		if input.TimeZone != nil {
+			if _, locationError := time.LoadLocation(*input.TimeZone); locationError != nil || *input.TimeZone == "" {
+				return ErrInvalidTimeZone
+			}
+			queryParams.TimeZone = *input.TimeZone
+		}
+
+		if updateError := queries.UpdateCalendar(ctx, queryParams); updateError != nil {
+			return fmt.Errorf("failed to update calendar: %w", updateError)
+		}
+
+		return nil
+	})
+	if transactionError != nil {
+		return transactionError
 	}
 
+	s.publish(input.CalendarID, events.TypeCalendarUpdated, nil)
 	return nil
 }
+
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+func (s *CalendarService) GetCalendarTimeZone(ctx context.Context, calendarID pgtype.UUID) (string, error) {
+	ctx, span := startSpan(ctx, "GetCalendarTimeZone", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return "", calendarError
+	}
+	return calendar.TimeZone, nil
+}
+
+func (s *CalendarService) DeleteCalendar(ctx context.Context, calendarID pgtype.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteCalendar", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	if _, calendarError := getCalendar(ctx, s.queries, calendarID); calendarError != nil {
+		return calendarError
+	}
+
+	if deletionError := s.queries.DeleteCalendarByID(ctx, calendarID); deletionError != nil {
+		return fmt.Errorf("failed to delete calendar: %w", deletionError)
+	}
+
+	s.publish(calendarID, events.TypeCalendarDeleted, nil)
+	return nil
+}
+
+type VoteDetails struct {
+	ParticipantID pgtype.UUID
+	Username      string
+	Preference    VotePreference
+}
+
+type TimeSlotDetails struct {
+	ID            pgtype.UUID
+	StartDate     time.Time
+	EndDate       time.Time
+	Votes         []VoteDetails
+	YesCount      int
+	IfNeedBeCount int
+	NoCount       int
+	Score         float64
+}
+
+type CalendarDetails struct {
+	ID                   pgtype.UUID
+	Title                string
+	Description          *string
+	Location             *string
+	TimeZone             string
+	AcceptResponsesUntil *time.Time
+	ResponsesRemaining   *time.Duration
+	Status               CalendarStatus
+	FinalTimeSlotID      *pgtype.UUID
+	IsOpen               bool
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	TimeSlots            []TimeSlotDetails
+}
+
+func (s *CalendarService) GetCalendar(ctx context.Context, calendarID pgtype.UUID) (CalendarDetails, error) {
+	ctx, span := startSpan(ctx, "GetCalendar", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return CalendarDetails{}, calendarError
+	}
+
+	timeSlots, timeSlotsError := s.queries.GetCalendarTimeSlotsByCalendarID(ctx, calendarID)
+	if timeSlotsError != nil {
+		return CalendarDetails{}, fmt.Errorf("failed to get calendar time slots: %w", timeSlotsError)
+	}
+
+	votes, votesError := s.queries.ListVotesByCalendarID(ctx, calendarID)
+	if votesError != nil {
+		return CalendarDetails{}, fmt.Errorf("failed to list calendar votes: %w", votesError)
+	}
+
+	votesBySlot := make(map[pgtype.UUID][]VoteDetails)
+	for _, vote := range votes {
+		votesBySlot[vote.CalendarTimeSlotID] = append(votesBySlot[vote.CalendarTimeSlotID], VoteDetails{
+			ParticipantID: vote.ParticipantID,
+			Username:      vote.Username,
+			Preference:    VotePreference(vote.Preference),
+		})
+	}
+
+	now := time.Now()
+	status := effectiveStatus(calendar, now)
+	details := CalendarDetails{
+		ID:          calendar.ID,
+		Title:       calendar.Title,
+		Description: calendar.Description,
+		Location:    calendar.Location,
+		TimeZone:    calendar.TimeZone,
+		Status:      status,
+		IsOpen:      status == CalendarStatusOpen,
+		CreatedAt:   calendar.CreatedAt.Time,
+		UpdatedAt:   calendar.UpdatedAt.Time,
+		TimeSlots:   make([]TimeSlotDetails, 0, len(timeSlots)),
+	}
+
+	if calendar.AcceptResponsesUntil.Valid {
+		acceptResponsesUntil := calendar.AcceptResponsesUntil.Time
+		responsesRemaining := max(acceptResponsesUntil.Sub(now), 0)
+		details.AcceptResponsesUntil = &acceptResponsesUntil
+		details.ResponsesRemaining = &responsesRemaining
+	}
+
+	if calendar.FinalTimeSlotID.Valid {
+		finalTimeSlotID := calendar.FinalTimeSlotID
+		details.FinalTimeSlotID = &finalTimeSlotID
+	}
+
+	for _, slot := range timeSlots {
+		slotDetails := TimeSlotDetails{
+			ID:        slot.ID,
+			StartDate: slot.StartDate.Time,
+			EndDate:   slot.EndDate.Time,
+			Votes:     votesBySlot[slot.ID],
+		}
+		if slotDetails.Votes == nil {
+			slotDetails.Votes = []VoteDetails{}
+		}
+
+		for _, vote := range slotDetails.Votes {
+			switch vote.Preference {
+			case PreferenceYes:
+				slotDetails.YesCount++
+			case PreferenceIfNeedBe:
+				slotDetails.IfNeedBeCount++
+			case PreferenceNo:
+				slotDetails.NoCount++
+			}
+			slotDetails.Score += vote.Preference.Weight()
+		}
+
+		details.TimeSlots = append(details.TimeSlots, slotDetails)
+	}
+
+	return details, nil
+}
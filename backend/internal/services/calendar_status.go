@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/utils"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CalendarStatus string
+
+const (
+	CalendarStatusDraft     CalendarStatus = "draft"
+	CalendarStatusOpen      CalendarStatus = "open"
+	CalendarStatusClosed    CalendarStatus = "closed"
+	CalendarStatusFinalized CalendarStatus = "finalized"
+	CalendarStatusCancelled CalendarStatus = "cancelled"
+)
+
+var calendarStatusTransitions = map[CalendarStatus][]CalendarStatus{
+	CalendarStatusDraft:     {CalendarStatusOpen, CalendarStatusCancelled},
+	CalendarStatusOpen:      {CalendarStatusClosed, CalendarStatusFinalized, CalendarStatusCancelled},
+	CalendarStatusClosed:    {CalendarStatusOpen, CalendarStatusFinalized, CalendarStatusCancelled},
+	CalendarStatusFinalized: {CalendarStatusClosed, CalendarStatusCancelled},
+	CalendarStatusCancelled: {},
+}
+
+func (s CalendarStatus) IsValid() bool {
+	_, exists := calendarStatusTransitions[s]
+	return exists
+}
+
+func (s CalendarStatus) CanTransitionTo(next CalendarStatus) bool {
+	return slices.Contains(calendarStatusTransitions[s], next)
+}
+
+// effectiveStatus reports an open calendar whose accept_responses_until has
+// passed as closed, so the deadline and the stored status never disagree.
+func effectiveStatus(calendar sqlc.Calendar, now time.Time) CalendarStatus {
+	status := CalendarStatus(calendar.Status)
+	if status == CalendarStatusOpen && !acceptsResponses(calendar, now) {
+		return CalendarStatusClosed
+	}
+	return status
+}
+
+func ensureTimeSlotsEditable(calendar sqlc.Calendar) error {
+	switch CalendarStatus(calendar.Status) {
+	case CalendarStatusFinalized, CalendarStatusCancelled:
+		return ErrCalendarLocked
+	}
+	return nil
+}
+
+type TransitionCalendarStatusInput struct {
+	CalendarID      pgtype.UUID
+	Status          CalendarStatus
+	FinalTimeSlotID *pgtype.UUID
+}
+
+func (s *CalendarService) TransitionCalendarStatus(ctx context.Context, input TransitionCalendarStatusInput) error {
+	ctx, span := startSpan(ctx, "TransitionCalendarStatus", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	eventData := map[string]any{
+		"status":             input.Status,
+		"final_time_slot_id": nil,
+	}
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		calendar, calendarError := getCalendar(ctx, queries, input.CalendarID)
+		if calendarError != nil {
+			return calendarError
+		}
+
+		currentStatus := effectiveStatus(calendar, time.Now())
+		if !currentStatus.CanTransitionTo(input.Status) {
+			return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidStatusTransition, currentStatus, input.Status)
+		}
+
+		if input.Status == CalendarStatusOpen && !acceptsResponses(calendar, time.Now()) {
+			return fmt.Errorf("%w: accept_responses_until has passed", ErrInvalidStatusTransition)
+		}
+
+		queryParams := sqlc.UpdateCalendarStatusParams{
+			ID:     calendar.ID,
+			Status: string(input.Status),
+		}
+
+		if input.Status == CalendarStatusFinalized {
+			if input.FinalTimeSlotID == nil {
+				return ErrFinalTimeSlotRequired
+			}
+			if _, timeSlotError := getCalendarTimeSlot(ctx, queries, calendar.ID, *input.FinalTimeSlotID); timeSlotError != nil {
+				return timeSlotError
+			}
+			queryParams.FinalTimeSlotID = *input.FinalTimeSlotID
+			eventData["final_time_slot_id"] = utils.UUIDToString(*input.FinalTimeSlotID)
+		}
+
+		if updateError := queries.UpdateCalendarStatus(ctx, queryParams); updateError != nil {
+			return fmt.Errorf("failed to update calendar status: %w", updateError)
+		}
+
+		return nil
+	})
+	if transactionError != nil {
+		return transactionError
+	}
+
+	s.publish(input.CalendarID, events.TypeStatusChanged, eventData)
+	if input.Status == CalendarStatusFinalized {
+		s.publish(input.CalendarID, events.TypeCalendarFinalized, map[string]any{
+			"final_time_slot_id": eventData["final_time_slot_id"],
+		})
+	}
+	return nil
+}
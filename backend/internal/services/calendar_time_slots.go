@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/metrics"
+	"meeting-planner/backend/internal/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type TimeSlotInput struct {
+	StartDate time.Time
+	EndDate   time.Time
+	// IsWallClock marks StartDate and EndDate as local wall-clock times that
+	// are placed in TimeZone, or in the calendar's zone when TimeZone is empty.
+	IsWallClock bool
+	TimeZone    string
+}
+
+func resolveTimeSlot(slot TimeSlotInput, calendarTimeZone string) (TimeSlotInput, error) {
+	if slot.IsWallClock {
+		timeZone := slot.TimeZone
+		if timeZone == "" {
+			timeZone = calendarTimeZone
+		}
+
+		location, locationError := time.LoadLocation(timeZone)
+		if locationError != nil {
+			return TimeSlotInput{}, fmt.Errorf("%w: unknown time zone %q", ErrInvalidTimeSlot, timeZone)
+		}
+
+		slot = TimeSlotInput{
+			StartDate: inLocation(slot.StartDate, location),
+			EndDate:   inLocation(slot.EndDate, location),
+		}
+	}
+
+	if !slot.EndDate.After(slot.StartDate) {
+		return TimeSlotInput{}, fmt.Errorf("%w: end_date must be after start_date", ErrInvalidTimeSlot)
+	}
+
+	return slot, nil
+}
+
+func inLocation(wallClock time.Time, location *time.Location) time.Time {
+	year, month, day := wallClock.Date()
+	hour, minute, second := wallClock.Clock()
+	return time.Date(year, month, day, hour, minute, second, 0, location)
+}
+
+type CreateCalendarTimeSlotsInput struct {
+	CalendarID pgtype.UUID
+	TimeSlots  []TimeSlotInput
+}
+
+func (s *CalendarService) CreateCalendarTimeSlots(ctx context.Context, input CreateCalendarTimeSlotsInput) ([]pgtype.UUID, error) {
+	ctx, span := startSpan(ctx, "CreateCalendarTimeSlots", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	var timeSlotIDs []pgtype.UUID
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		calendar, calendarError := getCalendar(ctx, queries, input.CalendarID)
+		if calendarError != nil {
+			return calendarError
+		}
+
+		if lockError := ensureTimeSlotsEditable(calendar); lockError != nil {
+			return lockError
+		}
+
+		var creationError error
+		timeSlotIDs, creationError = createTimeSlots(ctx, queries, calendar, input.TimeSlots)
+		if creationError != nil {
+			return creationError
+		}
+
+		return touchCalendar(ctx, queries, input.CalendarID)
+	})
+	if transactionError != nil {
+		return nil, transactionError
+	}
+
+	metrics.TimeSlotsCreated.Add(float64(len(timeSlotIDs)))
+
+	timeSlotIDStrings := make([]string, 0, len(timeSlotIDs))
+	for _, timeSlotID := range timeSlotIDs {
+		timeSlotIDStrings = append(timeSlotIDStrings, utils.UUIDToString(timeSlotID))
+	}
+	s.publish(input.CalendarID, events.TypeTimeSlotsCreated, map[string]any{"time_slot_ids": timeSlotIDStrings})
+
+	return timeSlotIDs, nil
+}
+
+func createTimeSlots(ctx context.Context, queries *sqlc.Queries, calendar sqlc.Calendar, timeSlots []TimeSlotInput) ([]pgtype.UUID, error) {
+	timeSlotIDs := make([]pgtype.UUID, 0, len(timeSlots))
+	rows := make([]sqlc.CreateCalendarTimeSlotsParams, 0, len(timeSlots))
+
+	for _, timeSlot := range timeSlots {
+		slot, resolvingError := resolveTimeSlot(timeSlot, calendar.TimeZone)
+		if resolvingError != nil {
+			return nil, resolvingError
+		}
+
+		timeSlotID := utils.NewUUID()
+		timeSlotIDs = append(timeSlotIDs, timeSlotID)
+		rows = append(rows, sqlc.CreateCalendarTimeSlotsParams{
+			ID:         timeSlotID,
+			CalendarID: calendar.ID,
+			StartDate:  toTimestamptz(&slot.StartDate),
+			EndDate:    toTimestamptz(&slot.EndDate),
+		})
+	}
+
+	if _, copyError := queries.CreateCalendarTimeSlots(ctx, rows); copyError != nil {
+		return nil, fmt.Errorf("failed to create calendar time slots: %w", copyError)
+	}
+
+	return timeSlotIDs, nil
+}
+
+// touchCalendar bumps updated_at after its time slots change, which is what
+// the iCalendar feed derives SEQUENCE and LAST-MODIFIED from.
+func touchCalendar(ctx context.Context, queries *sqlc.Queries, calendarID pgtype.UUID) error {
+	if touchError := queries.TouchCalendar(ctx, calendarID); touchError != nil {
+		return fmt.Errorf("failed to update calendar modification time: %w", touchError)
+	}
+	return nil
+}
+
+func getCalendarTimeSlot(ctx context.Context, queries *sqlc.Queries, calendarID pgtype.UUID, timeSlotID pgtype.UUID) (sqlc.CalendarTimeSlot, error) {
+	timeSlot, timeSlotError := queries.GetCalendarTimeSlotByID(ctx, timeSlotID)
+	if timeSlotError != nil {
+		if errors.Is(timeSlotError, pgx.ErrNoRows) {
+			return sqlc.CalendarTimeSlot{}, ErrTimeSlotNotFound
+		}
+		return sqlc.CalendarTimeSlot{}, fmt.Errorf("failed to get calendar time slot: %w", timeSlotError)
+	}
+
+	if timeSlot.CalendarID != calendarID {
+		return sqlc.CalendarTimeSlot{}, ErrTimeSlotNotFound
+	}
+
+	return timeSlot, nil
+}
+
+type UpdateCalendarTimeSlotInput struct {
+	CalendarID pgtype.UUID
+	TimeSlotID pgtype.UUID
+	TimeSlot   TimeSlotInput
+}
+
+func (s *CalendarService) UpdateCalendarTimeSlot(ctx context.Context, input UpdateCalendarTimeSlotInput) error {
+	ctx, span := startSpan(ctx, "UpdateCalendarTimeSlot", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, input.CalendarID)
+	if calendarError != nil {
+		return calendarError
+	}
+
+	if lockError := ensureTimeSlotsEditable(calendar); lockError != nil {
+		return lockError
+	}
+
+	if _, timeSlotError := getCalendarTimeSlot(ctx, s.queries, input.CalendarID, input.TimeSlotID); timeSlotError != nil {
+		return timeSlotError
+	}
+
+	slot, resolvingError := resolveTimeSlot(input.TimeSlot, calendar.TimeZone)
+	if resolvingError != nil {
+		return resolvingError
+	}
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		_, updateError := queries.UpdateCalendarTimeSlot(ctx, sqlc.UpdateCalendarTimeSlotParams{
+			ID:        input.TimeSlotID,
+			StartDate: toTimestamptz(&slot.StartDate),
+			EndDate:   toTimestamptz(&slot.EndDate),
+		})
+		if updateError != nil {
+			return fmt.Errorf("failed to update calendar time slot: %w", updateError)
+		}
+
+		return touchCalendar(ctx, queries, input.CalendarID)
+	})
+	if transactionError != nil {
+		return transactionError
+	}
+
+	s.publish(input.CalendarID, events.TypeTimeSlotUpdated, map[string]any{"time_slot_id": utils.UUIDToString(input.TimeSlotID)})
+	return nil
+}
+
+func (s *CalendarService) DeleteCalendarTimeSlot(ctx context.Context, calendarID pgtype.UUID, timeSlotID pgtype.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteCalendarTimeSlot", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return calendarError
+	}
+
+	if lockError := ensureTimeSlotsEditable(calendar); lockError != nil {
+		return lockError
+	}
+
+	if _, timeSlotError := getCalendarTimeSlot(ctx, s.queries, calendarID, timeSlotID); timeSlotError != nil {
+		return timeSlotError
+	}
+
+	transactionError := s.withTx(ctx, func(queries *sqlc.Queries) error {
+		if deletionError := queries.DeleteCalendarTimeSlotByID(ctx, timeSlotID); deletionError != nil {
+			return fmt.Errorf("failed to delete calendar time slot: %w", deletionError)
+		}
+
+		return touchCalendar(ctx, queries, calendarID)
+	})
+	if transactionError != nil {
+		return transactionError
+	}
+
+	s.publish(calendarID, events.TypeTimeSlotDeleted, map[string]any{"time_slot_id": utils.UUIDToString(timeSlotID)})
+	return nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/metrics"
+	"meeting-planner/backend/internal/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type VotePreference string
+
+const (
+	PreferenceYes      VotePreference = "yes"
+	PreferenceIfNeedBe VotePreference = "if_need_be"
+	PreferenceNo       VotePreference = "no"
+)
+
+func (p VotePreference) IsValid() bool {
+	switch p {
+	case PreferenceYes, PreferenceIfNeedBe, PreferenceNo:
+		return true
+	}
+	return false
+}
+
+func (p VotePreference) Weight() float64 {
+	switch p {
+	case PreferenceYes:
+		return 1
+	case PreferenceIfNeedBe:
+		return 0.5
+	}
+	return 0
+}
+
+type VoteInput struct {
+	TimeSlotID pgtype.UUID
+	Preference VotePreference
+}
+
+// countVotes is called once the transaction that stored votes has committed.
+func countVotes(votes []VoteInput) {
+	for _, vote := range votes {
+		metrics.VotesCast.WithLabelValues(string(vote.Preference)).Inc()
+	}
+}
+
+func ensureAcceptsResponses(calendar sqlc.Calendar) error {
+	if effectiveStatus(calendar, time.Now()) != CalendarStatusOpen {
+		return ErrResponsesClosed
+	}
+	return nil
+}
+
+func replaceParticipantVotes(ctx context.Context, queries *sqlc.Queries, calendarID pgtype.UUID, participantID pgtype.UUID, votes []VoteInput) error {
+	timeSlots, timeSlotsError := queries.GetCalendarTimeSlotsByCalendarID(ctx, calendarID)
+	if timeSlotsError != nil {
+		return fmt.Errorf("failed to get calendar time slots: %w", timeSlotsError)
+	}
+
+	calendarSlotIDs := make(map[pgtype.UUID]struct{}, len(timeSlots))
+	for _, slot := range timeSlots {
+		calendarSlotIDs[slot.ID] = struct{}{}
+	}
+
+	for _, vote := range votes {
+		if !vote.Preference.IsValid() {
+			return ErrInvalidVotePreference
+		}
+		if _, exists := calendarSlotIDs[vote.TimeSlotID]; !exists {
+			return ErrTimeSlotNotInCalendar
+		}
+	}
+
+	if deletionError := queries.DeleteVotesByParticipantID(ctx, participantID); deletionError != nil {
+		return fmt.Errorf("failed to delete votes: %w", deletionError)
+	}
+
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	for _, vote := range votes {
+		_, creationError := queries.CreateVote(ctx, sqlc.CreateVoteParams{
+			ID:                 utils.NewUUID(),
+			CalendarID:         calendarID,
+			CalendarTimeSlotID: vote.TimeSlotID,
+			ParticipantID:      participantID,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+			Preference:         string(vote.Preference),
+		})
+		if creationError != nil {
+			if isUniqueViolation(creationError, "idx_votes_participant_slot") {
+				return ErrVoteConflict
+			}
+			return fmt.Errorf("failed to create vote: %w", creationError)
+		}
+	}
+
+	return nil
+}
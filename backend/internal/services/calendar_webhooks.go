@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const webhookDeliveryLogLimit = 50
+
+type CreateWebhookInput struct {
+	CalendarID pgtype.UUID
+	URL        string
+	EventTypes []string
+}
+
+type CreatedWebhook struct {
+	ID     pgtype.UUID
+	Secret string
+}
+
+func (s *CalendarService) CreateWebhook(ctx context.Context, input CreateWebhookInput) (CreatedWebhook, error) {
+	ctx, span := startSpan(ctx, "CreateWebhook", calendarIDAttribute(input.CalendarID))
+	defer span.End()
+
+	if _, calendarError := getCalendar(ctx, s.queries, input.CalendarID); calendarError != nil {
+		return CreatedWebhook{}, calendarError
+	}
+
+	secret, secretError := utils.GenerateToken()
+	if secretError != nil {
+		return CreatedWebhook{}, fmt.Errorf("failed to generate webhook secret: %w", secretError)
+	}
+
+	eventTypes := input.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	webhookID, creationError := s.queries.CreateWebhook(ctx, sqlc.CreateWebhookParams{
+		CalendarID: input.CalendarID,
+		Url:        input.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	if creationError != nil {
+		return CreatedWebhook{}, fmt.Errorf("failed to create webhook: %w", creationError)
+	}
+
+	return CreatedWebhook{
+		ID:     webhookID,
+		Secret: secret,
+	}, nil
+}
+
+func (s *CalendarService) ListWebhooks(ctx context.Context, calendarID pgtype.UUID) ([]sqlc.Webhook, error) {
+	ctx, span := startSpan(ctx, "ListWebhooks", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	if _, calendarError := getCalendar(ctx, s.queries, calendarID); calendarError != nil {
+		return nil, calendarError
+	}
+
+	webhooks, listingError := s.queries.ListWebhooksByCalendarID(ctx, calendarID)
+	if listingError != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", listingError)
+	}
+
+	return webhooks, nil
+}
+
+func getWebhook(ctx context.Context, queries *sqlc.Queries, calendarID pgtype.UUID, webhookID pgtype.UUID) (sqlc.Webhook, error) {
+	webhook, webhookError := queries.GetWebhookByID(ctx, webhookID)
+	if webhookError != nil {
+		if errors.Is(webhookError, pgx.ErrNoRows) {
+			return sqlc.Webhook{}, ErrWebhookNotFound
+		}
+		return sqlc.Webhook{}, fmt.Errorf("failed to get webhook: %w", webhookError)
+	}
+
+	if webhook.CalendarID != calendarID {
+		return sqlc.Webhook{}, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func (s *CalendarService) DeleteWebhook(ctx context.Context, calendarID pgtype.UUID, webhookID pgtype.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteWebhook", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	if _, webhookError := getWebhook(ctx, s.queries, calendarID, webhookID); webhookError != nil {
+		return webhookError
+	}
+
+	if deletionError := s.queries.DeleteWebhookByID(ctx, webhookID); deletionError != nil {
+		return fmt.Errorf("failed to delete webhook: %w", deletionError)
+	}
+
+	return nil
+}
+
+func (s *CalendarService) ListWebhookDeliveries(ctx context.Context, calendarID pgtype.UUID, webhookID pgtype.UUID) ([]sqlc.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "ListWebhookDeliveries", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	if _, webhookError := getWebhook(ctx, s.queries, calendarID, webhookID); webhookError != nil {
+		return nil, webhookError
+	}
+
+	deliveries, listingError := s.queries.ListWebhookDeliveriesByWebhookID(ctx, sqlc.ListWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhookID,
+		Limit:     webhookDeliveryLogLimit,
+	})
+	if listingError != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", listingError)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/notifications"
+	"meeting-planner/backend/internal/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	deadlineBatchSize        = 100
+	deadlineSummaryTimeSlots = 5
+)
+
+// WatchDeadlines publishes a deadline_reached event and emails the organizer a
+// summary once for every open calendar whose accept_responses_until has
+// passed, checking every interval until ctx is cancelled.
+func (s *CalendarService) WatchDeadlines(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if notificationError := s.notifyReachedDeadlines(ctx); notificationError != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to notify reached deadlines", "error", notificationError)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *CalendarService) notifyReachedDeadlines(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "notifyReachedDeadlines")
+	defer span.End()
+
+	calendarIDs, listingError := s.queries.ListCalendarsPastDeadline(ctx, deadlineBatchSize)
+	if listingError != nil {
+		return fmt.Errorf("failed to list calendars past deadline: %w", listingError)
+	}
+
+	for _, calendarID := range calendarIDs {
+		// Several instances may race for the same calendar; only the one
+		// that flips deadline_notified_at publishes the event.
+		claimedRows, claimingError := s.queries.MarkCalendarDeadlineNotified(ctx, calendarID)
+		if claimingError != nil {
+			return fmt.Errorf("failed to mark calendar deadline notified: %w", claimingError)
+		}
+		if claimedRows == 0 {
+			continue
+		}
+
+		s.publish(calendarID, events.TypeDeadlineReached, map[string]any{
+			"calendar_id": utils.UUIDToString(calendarID),
+		})
+
+		if summaryError := s.sendDeadlineSummary(ctx, calendarID); summaryError != nil {
+			slog.ErrorContext(ctx, "failed to send deadline summary",
+				"calendar_id", utils.UUIDToString(calendarID),
+				"error", summaryError,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *CalendarService) sendDeadlineSummary(ctx context.Context, calendarID pgtype.UUID) error {
+	if s.notifier == nil {
+		return nil
+	}
+
+	calendar, calendarError := getCalendar(ctx, s.queries, calendarID)
+	if calendarError != nil {
+		return calendarError
+	}
+	if calendar.OrganizerEmail == nil {
+		return nil
+	}
+
+	results, resultsError := s.GetCalendarResults(ctx, calendarID, RankingOptions{})
+	if resultsError != nil {
+		return resultsError
+	}
+
+	location, locationError := time.LoadLocation(calendar.TimeZone)
+	if locationError != nil {
+		location = time.UTC
+	}
+
+	notice := notifications.DeadlineSummaryNotice{
+		To:           *calendar.OrganizerEmail,
+		CalendarID:   utils.UUIDToString(calendar.ID),
+		Title:        calendar.Title,
+		Location:     location,
+		Participants: results.Participants,
+	}
+
+	for _, slot := range results.TimeSlots[:min(len(results.TimeSlots), deadlineSummaryTimeSlots)] {
+		notice.TimeSlots = append(notice.TimeSlots, notifications.TimeSlotSummary{
+			StartDate:     slot.StartDate,
+			EndDate:       slot.EndDate,
+			YesCount:      slot.YesCount,
+			IfNeedBeCount: slot.IfNeedBeCount,
+			NoCount:       slot.NoCount,
+		})
+	}
+
+	s.notifier.DeadlineSummary(notice)
+	return nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"meeting-planner/backend/internal/db/sqlc"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RankingOptions struct {
+	// Quorum is the minimum number of participants who answered yes or if
+	// need be for a slot to be eligible as the best slot. Zero disables it.
+	Quorum int
+}
+
+type RankedTimeSlot struct {
+	TimeSlotID          pgtype.UUID
+	StartDate           time.Time
+	EndDate             time.Time
+	Rank                int
+	Score               float64
+	YesCount            int
+	IfNeedBeCount       int
+	NoCount             int
+	MissingParticipants []string
+	MeetsQuorum         bool
+}
+
+type CalendarResults struct {
+	Participants   []string
+	TimeSlots      []RankedTimeSlot
+	BestTimeSlotID *pgtype.UUID
+}
+
+// RankTimeSlots counts every participant of the calendar, so a participant
+// who has not voted for a slot is missing from it and does not count towards
+// its quorum.
+func RankTimeSlots(timeSlots []sqlc.CalendarTimeSlot, calendarParticipants []sqlc.Participant, votes []sqlc.ListVotesByCalendarIDRow, options RankingOptions) CalendarResults {
+	preferencesBySlot := make(map[pgtype.UUID]map[pgtype.UUID]VotePreference, len(timeSlots))
+	for _, vote := range votes {
+		if preferencesBySlot[vote.CalendarTimeSlotID] == nil {
+			preferencesBySlot[vote.CalendarTimeSlotID] = make(map[pgtype.UUID]VotePreference)
+		}
+		preferencesBySlot[vote.CalendarTimeSlotID][vote.ParticipantID] = VotePreference(vote.Preference)
+	}
+
+	sortedParticipants := slices.SortedFunc(slices.Values(calendarParticipants), func(a sqlc.Participant, b sqlc.Participant) int {
+		return strings.Compare(a.DisplayName, b.DisplayName)
+	})
+	participants := make([]string, 0, len(sortedParticipants))
+	for _, participant := range sortedParticipants {
+		participants = append(participants, participant.DisplayName)
+	}
+
+	rankedSlots := make([]RankedTimeSlot, 0, len(timeSlots))
+	for _, slot := range timeSlots {
+		rankedSlot := RankedTimeSlot{
+			TimeSlotID:          slot.ID,
+			StartDate:           slot.StartDate.Time,
+			EndDate:             slot.EndDate.Time,
+			MissingParticipants: []string{},
+		}
+
+		preferences := preferencesBySlot[slot.ID]
+		for _, participant := range sortedParticipants {
+			preference, hasVoted := preferences[participant.ID]
+			switch preference {
+			case PreferenceYes:
+				rankedSlot.YesCount++
+			case PreferenceIfNeedBe:
+				rankedSlot.IfNeedBeCount++
+			case PreferenceNo:
+				rankedSlot.NoCount++
+			}
+			if !hasVoted {
+				rankedSlot.MissingParticipants = append(rankedSlot.MissingParticipants, participant.DisplayName)
+			}
+			rankedSlot.Score += preference.Weight()
+		}
+
+		rankedSlot.MeetsQuorum = rankedSlot.YesCount+rankedSlot.IfNeedBeCount >= options.Quorum
+		rankedSlots = append(rankedSlots, rankedSlot)
+	}
+
+	slices.SortStableFunc(rankedSlots, compareRankedTimeSlots)
+
+	results := CalendarResults{
+		Participants: participants,
+		TimeSlots:    rankedSlots,
+	}
+
+	for index := range rankedSlots {
+		rankedSlots[index].Rank = index + 1
+		if results.BestTimeSlotID == nil && rankedSlots[index].MeetsQuorum && rankedSlots[index].Score > 0 {
+			bestTimeSlotID := rankedSlots[index].TimeSlotID
+			results.BestTimeSlotID = &bestTimeSlotID
+		}
+	}
+
+	return results
+}
+
+// compareRankedTimeSlots orders slots that meet the quorum first, then by
+// score, breaking ties by earliest start and then shortest duration. The slot
+// ID is the last resort so that equal slots always come back in the same
+// order.
+func compareRankedTimeSlots(a RankedTimeSlot, b RankedTimeSlot) int {
+	if a.MeetsQuorum != b.MeetsQuorum {
+		if a.MeetsQuorum {
+			return -1
+		}
+		return 1
+	}
+	if a.Score != b.Score {
+		if a.Score > b.Score {
+			return -1
+		}
+		return 1
+	}
+	if startComparison := a.StartDate.Compare(b.StartDate); startComparison != 0 {
+		return startComparison
+	}
+	if durationComparison := a.EndDate.Sub(a.StartDate) - b.EndDate.Sub(b.StartDate); durationComparison != 0 {
+		if durationComparison < 0 {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a.TimeSlotID.Bytes[:], b.TimeSlotID.Bytes[:])
+}
+
+func (s *CalendarService) GetCalendarResults(ctx context.Context, calendarID pgtype.UUID, options RankingOptions) (CalendarResults, error) {
+	ctx, span := startSpan(ctx, "GetCalendarResults", calendarIDAttribute(calendarID))
+	defer span.End()
+
+	if _, calendarError := getCalendar(ctx, s.queries, calendarID); calendarError != nil {
+		return CalendarResults{}, calendarError
+	}
+
+	timeSlots, timeSlotsError := s.queries.GetCalendarTimeSlotsByCalendarID(ctx, calendarID)
+	if timeSlotsError != nil {
+		return CalendarResults{}, fmt.Errorf("failed to get calendar time slots: %w", timeSlotsError)
+	}
+
+	participants, participantsError := s.queries.ListParticipantsByCalendarID(ctx, calendarID)
+	if participantsError != nil {
+		return CalendarResults{}, fmt.Errorf("failed to list calendar participants: %w", participantsError)
+	}
+
+	votes, votesError := s.queries.ListVotesByCalendarID(ctx, calendarID)
+	if votesError != nil {
+		return CalendarResults{}, fmt.Errorf("failed to list calendar votes: %w", votesError)
+	}
+
+	return RankTimeSlots(timeSlots, participants, votes, options), nil
+}
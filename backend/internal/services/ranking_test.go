@@ -0,0 +1,228 @@
+package services
+
+import (
+	"meeting-planner/backend/internal/db/sqlc"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var rankingBaseTime = time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+type testTimeSlot struct {
+	name     string
+	idByte   byte
+	start    time.Duration
+	duration time.Duration
+}
+
+type testVote struct {
+	participant string
+	timeSlot    string
+	preference  VotePreference
+}
+
+func testUUID(prefix byte, value byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{prefix, 15: value}, Valid: true}
+}
+
+func TestRankTimeSlots(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		timeSlots            []testTimeSlot
+		participants         []string
+		votes                []testVote
+		quorum               int
+		expectedOrder        []string
+		expectedBest         string
+		expectedMissing      map[string][]string
+		expectedParticipants []string
+	}{
+		{
+			name: "higher score wins over earlier start",
+			timeSlots: []testTimeSlot{
+				{name: "early", idByte: 1, start: 0, duration: time.Hour},
+				{name: "late", idByte: 2, start: 2 * time.Hour, duration: time.Hour},
+			},
+			participants: []string{"Ann", "Bob"},
+			votes: []testVote{
+				{"Ann", "early", PreferenceYes},
+				{"Bob", "early", PreferenceIfNeedBe},
+				{"Ann", "late", PreferenceYes},
+				{"Bob", "late", PreferenceYes},
+			},
+			expectedOrder: []string{"late", "early"},
+			expectedBest:  "late",
+		},
+		{
+			name: "equal score prefers the earliest start over more yes votes",
+			timeSlots: []testTimeSlot{
+				{name: "yes and no", idByte: 1, start: time.Hour, duration: time.Hour},
+				{name: "if need be", idByte: 2, start: 0, duration: time.Hour},
+			},
+			participants: []string{"Ann", "Bob"},
+			votes: []testVote{
+				{"Ann", "if need be", PreferenceIfNeedBe},
+				{"Bob", "if need be", PreferenceIfNeedBe},
+				{"Ann", "yes and no", PreferenceYes},
+				{"Bob", "yes and no", PreferenceNo},
+			},
+			expectedOrder: []string{"if need be", "yes and no"},
+			expectedBest:  "if need be",
+		},
+		{
+			name: "equal votes prefer the earliest start",
+			timeSlots: []testTimeSlot{
+				{name: "late", idByte: 1, start: 2 * time.Hour, duration: time.Hour},
+				{name: "early", idByte: 2, start: 0, duration: time.Hour},
+			},
+			participants: []string{"Ann"},
+			votes: []testVote{
+				{"Ann", "late", PreferenceYes},
+				{"Ann", "early", PreferenceYes},
+			},
+			expectedOrder: []string{"early", "late"},
+			expectedBest:  "early",
+		},
+		{
+			name: "equal start prefers the shortest duration",
+			timeSlots: []testTimeSlot{
+				{name: "long", idByte: 1, start: 0, duration: 2 * time.Hour},
+				{name: "short", idByte: 2, start: 0, duration: time.Hour},
+			},
+			participants: []string{"Ann"},
+			votes: []testVote{
+				{"Ann", "long", PreferenceYes},
+				{"Ann", "short", PreferenceYes},
+			},
+			expectedOrder: []string{"short", "long"},
+			expectedBest:  "short",
+		},
+		{
+			name: "identical slots are ordered by ID",
+			timeSlots: []testTimeSlot{
+				{name: "second", idByte: 2, start: 0, duration: time.Hour},
+				{name: "first", idByte: 1, start: 0, duration: time.Hour},
+			},
+			participants:  []string{"Ann"},
+			votes:         []testVote{{"Ann", "second", PreferenceYes}, {"Ann", "first", PreferenceYes}},
+			expectedOrder: []string{"first", "second"},
+			expectedBest:  "first",
+		},
+		{
+			name: "slots meeting the quorum come first",
+			timeSlots: []testTimeSlot{
+				{name: "two yes", idByte: 1, start: 0, duration: time.Hour},
+				{name: "three if need be", idByte: 2, start: time.Hour, duration: time.Hour},
+			},
+			participants: []string{"Ann", "Bob", "Cid"},
+			votes: []testVote{
+				{"Ann", "two yes", PreferenceYes},
+				{"Bob", "two yes", PreferenceYes},
+				{"Cid", "two yes", PreferenceNo},
+				{"Ann", "three if need be", PreferenceIfNeedBe},
+				{"Bob", "three if need be", PreferenceIfNeedBe},
+				{"Cid", "three if need be", PreferenceIfNeedBe},
+			},
+			quorum:        3,
+			expectedOrder: []string{"three if need be", "two yes"},
+			expectedBest:  "three if need be",
+			expectedMissing: map[string][]string{
+				"two yes":          {},
+				"three if need be": {},
+			},
+		},
+		{
+			name: "participants who have not voted are missing and count against the quorum",
+			timeSlots: []testTimeSlot{
+				{name: "slot", idByte: 1, start: 0, duration: time.Hour},
+			},
+			participants:         []string{"Cid", "Ann", "Bob"},
+			votes:                []testVote{{"Ann", "slot", PreferenceYes}, {"Bob", "slot", PreferenceYes}},
+			quorum:               3,
+			expectedOrder:        []string{"slot"},
+			expectedMissing:      map[string][]string{"slot": {"Cid"}},
+			expectedParticipants: []string{"Ann", "Bob", "Cid"},
+		},
+		{
+			name: "no best slot without positive votes and a no vote is not missing",
+			timeSlots: []testTimeSlot{
+				{name: "slot", idByte: 1, start: 0, duration: time.Hour},
+			},
+			participants:    []string{"Ann"},
+			votes:           []testVote{{"Ann", "slot", PreferenceNo}},
+			expectedOrder:   []string{"slot"},
+			expectedMissing: map[string][]string{"slot": {}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			calendarID := testUUID(0xca, 1)
+			slotIDs := map[string]pgtype.UUID{}
+			slotNames := map[pgtype.UUID]string{}
+			timeSlots := make([]sqlc.CalendarTimeSlot, 0, len(testCase.timeSlots))
+			for _, slot := range testCase.timeSlots {
+				slotID := testUUID(0x51, slot.idByte)
+				slotIDs[slot.name] = slotID
+				slotNames[slotID] = slot.name
+				startDate := rankingBaseTime.Add(slot.start)
+				timeSlots = append(timeSlots, sqlc.CalendarTimeSlot{
+					ID:         slotID,
+					CalendarID: calendarID,
+					StartDate:  pgtype.Timestamptz{Time: startDate, Valid: true},
+					EndDate:    pgtype.Timestamptz{Time: startDate.Add(slot.duration), Valid: true},
+				})
+			}
+
+			participantIDs := map[string]pgtype.UUID{}
+			participants := make([]sqlc.Participant, 0, len(testCase.participants))
+			for participantIndex, displayName := range testCase.participants {
+				participantID := testUUID(0x9a, byte(participantIndex+1))
+				participantIDs[displayName] = participantID
+				participants = append(participants, sqlc.Participant{ID: participantID, CalendarID: calendarID, DisplayName: displayName})
+			}
+
+			votes := make([]sqlc.ListVotesByCalendarIDRow, 0, len(testCase.votes))
+			for _, vote := range testCase.votes {
+				votes = append(votes, sqlc.ListVotesByCalendarIDRow{
+					CalendarID:         calendarID,
+					CalendarTimeSlotID: slotIDs[vote.timeSlot],
+					ParticipantID:      participantIDs[vote.participant],
+					Username:           vote.participant,
+					Preference:         string(vote.preference),
+				})
+			}
+
+			results := RankTimeSlots(timeSlots, participants, votes, RankingOptions{Quorum: testCase.quorum})
+
+			rankedOrder := make([]string, 0, len(results.TimeSlots))
+			for slotIndex, rankedSlot := range results.TimeSlots {
+				rankedOrder = append(rankedOrder, slotNames[rankedSlot.TimeSlotID])
+				if rankedSlot.Rank != slotIndex+1 {
+					t.Errorf("%s has rank %d, want %d", slotNames[rankedSlot.TimeSlotID], rankedSlot.Rank, slotIndex+1)
+				}
+				if expectedMissing, hasExpectation := testCase.expectedMissing[slotNames[rankedSlot.TimeSlotID]]; hasExpectation && !slices.Equal(rankedSlot.MissingParticipants, expectedMissing) {
+					t.Errorf("%s misses %v, want %v", slotNames[rankedSlot.TimeSlotID], rankedSlot.MissingParticipants, expectedMissing)
+				}
+			}
+			if !slices.Equal(rankedOrder, testCase.expectedOrder) {
+				t.Errorf("order = %v, want %v", rankedOrder, testCase.expectedOrder)
+			}
+
+			bestSlot := ""
+			if results.BestTimeSlotID != nil {
+				bestSlot = slotNames[*results.BestTimeSlotID]
+			}
+			if bestSlot != testCase.expectedBest {
+				t.Errorf("best slot = %q, want %q", bestSlot, testCase.expectedBest)
+			}
+
+			if testCase.expectedParticipants != nil && !slices.Equal(results.Participants, testCase.expectedParticipants) {
+				t.Errorf("participants = %v, want %v", results.Participants, testCase.expectedParticipants)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	MaxGeneratedTimeSlots  = 500
+	MinGeneratedSlotLength = time.Minute
+)
+
+var (
+	ErrInvalidTimeSlotPattern = errors.New("invalid time slot pattern")
+	ErrTooManyTimeSlots       = fmt.Errorf("time slot pattern generates more than %d slots", MaxGeneratedTimeSlots)
+)
+
+type GenerateTimeSlotsInput struct {
+	StartDate      time.Time
+	EndDate        time.Time
+	DailyStartTime time.Duration
+	DailyEndTime   time.Duration
+	Duration       time.Duration
+	IsOverlapping  bool
+	IsWholeDay     bool
+	Location       *time.Location
+}
+
+func GenerateTimeSlots(input GenerateTimeSlotsInput) ([]TimeSlotInput, error) {
+	location := input.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	startYear, startMonth, startDay := input.StartDate.Date()
+	endYear, endMonth, endDay := input.EndDate.Date()
+	firstDate := time.Date(startYear, startMonth, startDay, 0, 0, 0, 0, time.UTC)
+	lastDate := time.Date(endYear, endMonth, endDay, 0, 0, 0, 0, time.UTC)
+
+	if lastDate.Before(firstDate) {
+		return nil, fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidTimeSlotPattern)
+	}
+
+	generated := []TimeSlotInput{}
+
+	if input.IsWholeDay {
+		for date := firstDate; !date.After(lastDate); date = date.AddDate(0, 0, 1) {
+			if len(generated) == MaxGeneratedTimeSlots {
+				return nil, ErrTooManyTimeSlots
+			}
+
+			generated = append(generated, TimeSlotInput{
+				StartDate: wallClockTime(date, 0, location),
+				EndDate:   wallClockTime(date, 24*time.Hour-time.Second, location),
+			})
+		}
+		return generated, nil
+	}
+
+	if input.Duration < MinGeneratedSlotLength {
+		return nil, fmt.Errorf("%w: duration must be at least %v", ErrInvalidTimeSlotPattern, MinGeneratedSlotLength)
+	}
+
+	if input.DailyStartTime < 0 || input.DailyEndTime > 24*time.Hour || input.DailyEndTime <= input.DailyStartTime {
+		return nil, fmt.Errorf("%w: daily_end_time must be after daily_start_time", ErrInvalidTimeSlotPattern)
+	}
+
+	interval := input.Duration
+	if input.IsOverlapping {
+		interval = input.Duration / 2
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval between slots must be positive", ErrInvalidTimeSlotPattern)
+	}
+
+	for date := firstDate; !date.After(lastDate); date = date.AddDate(0, 0, 1) {
+		for offset := input.DailyStartTime; offset+input.Duration <= input.DailyEndTime; offset += interval {
+			if len(generated) == MaxGeneratedTimeSlots {
+				return nil, ErrTooManyTimeSlots
+			}
+
+			slotStart := wallClockTime(date, offset, location)
+			slotEnd := wallClockTime(date, offset+input.Duration, location)
+			if !slotEnd.After(slotStart) {
+				continue
+			}
+
+			generated = append(generated, TimeSlotInput{
+				StartDate: slotStart,
+				EndDate:   slotEnd,
+			})
+		}
+	}
+
+	return generated, nil
+}
+
+func wallClockTime(date time.Time, offset time.Duration, location *time.Location) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, int(offset/time.Second), 0, location)
+}
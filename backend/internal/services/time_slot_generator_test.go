@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, locationError := time.LoadLocation(name)
+	if locationError != nil {
+		t.Fatalf("failed to load %s: %v", name, locationError)
+	}
+	return location
+}
+
+func mustParseDate(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	date, parsingError := time.Parse(time.DateOnly, value)
+	if parsingError != nil {
+		t.Fatalf("invalid date %s: %v", value, parsingError)
+	}
+	return date
+}
+
+// The expected slots of the cases outside DST transitions are the local
+// times client/src/utils/generateTimeSlots.ts produces for the same input,
+// with the offset of the requested time zone added.
+func TestGenerateTimeSlots(t *testing.T) {
+	warsaw := mustLoadLocation(t, "Europe/Warsaw")
+
+	testCases := []struct {
+		name           string
+		startDate      string
+		endDate        string
+		dailyStartTime time.Duration
+		dailyEndTime   time.Duration
+		duration       time.Duration
+		isOverlapping  bool
+		isWholeDay     bool
+		location       *time.Location
+		expected       [][2]string
+	}{
+		{
+			name:           "back to back over two days",
+			startDate:      "2026-01-10",
+			endDate:        "2026-01-11",
+			dailyStartTime: 18 * time.Hour,
+			dailyEndTime:   22 * time.Hour,
+			duration:       2 * time.Hour,
+			location:       time.UTC,
+			expected: [][2]string{
+				{"2026-01-10T18:00:00Z", "2026-01-10T20:00:00Z"},
+				{"2026-01-10T20:00:00Z", "2026-01-10T22:00:00Z"},
+				{"2026-01-11T18:00:00Z", "2026-01-11T20:00:00Z"},
+				{"2026-01-11T20:00:00Z", "2026-01-11T22:00:00Z"},
+			},
+		},
+		{
+			name:           "overlapping by half the duration",
+			startDate:      "2026-01-10",
+			endDate:        "2026-01-10",
+			dailyStartTime: 18 * time.Hour,
+			dailyEndTime:   22 * time.Hour,
+			duration:       2 * time.Hour,
+			isOverlapping:  true,
+			location:       warsaw,
+			expected: [][2]string{
+				{"2026-01-10T18:00:00+01:00", "2026-01-10T20:00:00+01:00"},
+				{"2026-01-10T19:00:00+01:00", "2026-01-10T21:00:00+01:00"},
+				{"2026-01-10T20:00:00+01:00", "2026-01-10T22:00:00+01:00"},
+			},
+		},
+		{
+			name:           "fractional overlapping duration",
+			startDate:      "2026-07-01",
+			endDate:        "2026-07-01",
+			dailyStartTime: 9 * time.Hour,
+			dailyEndTime:   12 * time.Hour,
+			duration:       90 * time.Minute,
+			isOverlapping:  true,
+			location:       warsaw,
+			expected: [][2]string{
+				{"2026-07-01T09:00:00+02:00", "2026-07-01T10:30:00+02:00"},
+				{"2026-07-01T09:45:00+02:00", "2026-07-01T11:15:00+02:00"},
+				{"2026-07-01T10:30:00+02:00", "2026-07-01T12:00:00+02:00"},
+			},
+		},
+		{
+			name:           "slot that does not fit the window is skipped",
+			startDate:      "2026-01-10",
+			endDate:        "2026-01-10",
+			dailyStartTime: 18 * time.Hour,
+			dailyEndTime:   22 * time.Hour,
+			duration:       3 * time.Hour,
+			location:       time.UTC,
+			expected: [][2]string{
+				{"2026-01-10T18:00:00Z", "2026-01-10T21:00:00Z"},
+			},
+		},
+		{
+			name:           "one minute slots",
+			startDate:      "2026-01-10",
+			endDate:        "2026-01-10",
+			dailyStartTime: 9 * time.Hour,
+			dailyEndTime:   9*time.Hour + 2*time.Minute,
+			duration:       time.Minute,
+			isOverlapping:  true,
+			location:       time.UTC,
+			expected: [][2]string{
+				{"2026-01-10T09:00:00Z", "2026-01-10T09:01:00Z"},
+				{"2026-01-10T09:00:30Z", "2026-01-10T09:01:30Z"},
+				{"2026-01-10T09:01:00Z", "2026-01-10T09:02:00Z"},
+			},
+		},
+		{
+			name:       "whole days",
+			startDate:  "2026-01-10",
+			endDate:    "2026-01-12",
+			isWholeDay: true,
+			location:   warsaw,
+			expected: [][2]string{
+				{"2026-01-10T00:00:00+01:00", "2026-01-10T23:59:59+01:00"},
+				{"2026-01-11T00:00:00+01:00", "2026-01-11T23:59:59+01:00"},
+				{"2026-01-12T00:00:00+01:00", "2026-01-12T23:59:59+01:00"},
+			},
+		},
+		{
+			name:       "whole day defaults to UTC",
+			startDate:  "2026-01-10",
+			endDate:    "2026-01-10",
+			isWholeDay: true,
+			expected: [][2]string{
+				{"2026-01-10T00:00:00Z", "2026-01-10T23:59:59Z"},
+			},
+		},
+		{
+			// Clocks jump from 02:00 to 03:00; the 02:00-03:00 slot does not
+			// exist and the slot ending at 02:00 ends at 03:00 summer time,
+			// the same instant.
+			name:       "whole day across spring forward",
+			startDate:  "2026-03-29",
+			endDate:    "2026-03-29",
+			isWholeDay: true,
+			location:   warsaw,
+			expected: [][2]string{
+				{"2026-03-29T00:00:00+01:00", "2026-03-29T23:59:59+02:00"},
+			},
+		},
+		{
+			name:           "hourly slots across spring forward",
+			startDate:      "2026-03-29",
+			endDate:        "2026-03-29",
+			dailyStartTime: time.Hour,
+			dailyEndTime:   4 * time.Hour,
+			duration:       time.Hour,
+			location:       warsaw,
+			expected: [][2]string{
+				{"2026-03-29T01:00:00+01:00", "2026-03-29T03:00:00+02:00"},
+				{"2026-03-29T03:00:00+02:00", "2026-03-29T04:00:00+02:00"},
+			},
+		},
+		{
+			name:       "whole day across fall back",
+			startDate:  "2026-10-25",
+			endDate:    "2026-10-25",
+			isWholeDay: true,
+			location:   warsaw,
+			expected: [][2]string{
+				{"2026-10-25T00:00:00+02:00", "2026-10-25T23:59:59+01:00"},
+			},
+		},
+		{
+			// 02:00-03:00 happens twice; the slots keep their wall-clock
+			// times and the one starting at 01:00 lasts two hours.
+			name:           "hourly slots across fall back",
+			startDate:      "2026-10-25",
+			endDate:        "2026-10-25",
+			dailyStartTime: time.Hour,
+			dailyEndTime:   4 * time.Hour,
+			duration:       time.Hour,
+			location:       warsaw,
+			expected: [][2]string{
+				{"2026-10-25T01:00:00+02:00", "2026-10-25T02:00:00+01:00"},
+				{"2026-10-25T02:00:00+01:00", "2026-10-25T03:00:00+01:00"},
+				{"2026-10-25T03:00:00+01:00", "2026-10-25T04:00:00+01:00"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generated, generationError := GenerateTimeSlots(GenerateTimeSlotsInput{
+				StartDate:      mustParseDate(t, testCase.startDate),
+				EndDate:        mustParseDate(t, testCase.endDate),
+				DailyStartTime: testCase.dailyStartTime,
+				DailyEndTime:   testCase.dailyEndTime,
+				Duration:       testCase.duration,
+				IsOverlapping:  testCase.isOverlapping,
+				IsWholeDay:     testCase.isWholeDay,
+				Location:       testCase.location,
+			})
+			if generationError != nil {
+				t.Fatalf("GenerateTimeSlots: %v", generationError)
+			}
+
+			if len(generated) != len(testCase.expected) {
+				t.Fatalf("generated %d slots, want %d: %v", len(generated), len(testCase.expected), generated)
+			}
+			for slotIndex, slot := range generated {
+				startDate := slot.StartDate.Format(time.RFC3339)
+				endDate := slot.EndDate.Format(time.RFC3339)
+				if startDate != testCase.expected[slotIndex][0] || endDate != testCase.expected[slotIndex][1] {
+					t.Errorf("slot %d = %s - %s, want %s - %s", slotIndex, startDate, endDate, testCase.expected[slotIndex][0], testCase.expected[slotIndex][1])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTimeSlotsRejectsInvalidPatterns(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         GenerateTimeSlotsInput
+		expectedError error
+	}{
+		{
+			name: "end date before start date",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-11"), EndDate: mustParseDate(t, "2026-01-10"),
+				DailyStartTime: 9 * time.Hour, DailyEndTime: 17 * time.Hour, Duration: time.Hour,
+			},
+			expectedError: ErrInvalidTimeSlotPattern,
+		},
+		{
+			name: "daily end before daily start",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-10"), EndDate: mustParseDate(t, "2026-01-10"),
+				DailyStartTime: 17 * time.Hour, DailyEndTime: 9 * time.Hour, Duration: time.Hour,
+			},
+			expectedError: ErrInvalidTimeSlotPattern,
+		},
+		{
+			name: "zero duration",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-10"), EndDate: mustParseDate(t, "2026-01-10"),
+				DailyStartTime: 9 * time.Hour, DailyEndTime: 17 * time.Hour,
+			},
+			expectedError: ErrInvalidTimeSlotPattern,
+		},
+		{
+			name: "sub-minute overlapping duration",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-10"), EndDate: mustParseDate(t, "2026-01-10"),
+				DailyStartTime: 9 * time.Hour, DailyEndTime: 17 * time.Hour, Duration: time.Nanosecond, IsOverlapping: true,
+			},
+			expectedError: ErrInvalidTimeSlotPattern,
+		},
+		{
+			name: "sub-minute duration",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-10"), EndDate: mustParseDate(t, "2026-01-10"),
+				DailyStartTime: 9 * time.Hour, DailyEndTime: 17 * time.Hour, Duration: 59 * time.Second,
+			},
+			expectedError: ErrInvalidTimeSlotPattern,
+		},
+		{
+			name: "more slots than allowed",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-10"), EndDate: mustParseDate(t, "2026-01-10"),
+				DailyStartTime: 0, DailyEndTime: 24 * time.Hour, Duration: time.Minute,
+			},
+			expectedError: ErrTooManyTimeSlots,
+		},
+		{
+			name: "more whole days than allowed",
+			input: GenerateTimeSlotsInput{
+				StartDate: mustParseDate(t, "2026-01-01"), EndDate: mustParseDate(t, "2028-01-01"), IsWholeDay: true,
+			},
+			expectedError: ErrTooManyTimeSlots,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generated, generationError := GenerateTimeSlots(testCase.input)
+			if !errors.Is(generationError, testCase.expectedError) {
+				t.Errorf("GenerateTimeSlots = %d slots, %v; want %v", len(generated), generationError, testCase.expectedError)
+			}
+		})
+	}
+}
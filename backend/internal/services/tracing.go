@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"meeting-planner/backend/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("meeting-planner/backend/internal/services")
+
+// startSpan starts a child span named after a CalendarService method. Errors
+// are recorded on the query spans and on the request span, so service spans
+// only carry timing and the calendar they worked on.
+func startSpan(ctx context.Context, methodName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "CalendarService."+methodName, trace.WithAttributes(attributes...))
+}
+
+func calendarIDAttribute(calendarID pgtype.UUID) attribute.KeyValue {
+	return attribute.String("calendar.id", utils.UUIDToString(calendarID))
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+func GenerateToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, readError := rand.Read(tokenBytes); readError != nil {
+		return "", fmt.Errorf("failed to generate token: %w", readError)
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+func HashToken(token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(tokenHash[:])
+}
+
+func TokenMatchesHash(token string, tokenHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(tokenHash)) == 1
+}
@@ -32,3 +32,10 @@ func UUIDToString(pgtypeUUID pgtype.UUID) string {
 
 	return parsedUUID.String()
 }
+
+func NewUUID() pgtype.UUID {
+	return pgtype.UUID{
+		Bytes: [16]byte(uuid.New()),
+		Valid: true,
+	}
+}
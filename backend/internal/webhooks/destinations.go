@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrDestinationNotAllowed = errors.New("webhook destination is not allowed")
+
+// blockedNetworks are reachable only from inside the deployment: loopback,
+// private and link-local ranges (which include cloud metadata endpoints such
+// as 169.254.169.254), carrier-grade NAT and unspecified addresses.
+var blockedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// destinationGuard refuses connections to internal addresses unless they are
+// in the allowed networks. It runs as the dialer's Control function, after
+// DNS resolution, so a host name that resolves to an internal address (or
+// starts doing so later) is refused as well.
+type destinationGuard struct {
+	allowedNetworks []netip.Prefix
+}
+
+// parseNetworks accepts single addresses and CIDR ranges.
+func parseNetworks(networks []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+
+	for _, network := range networks {
+		network = strings.TrimSpace(network)
+		if network == "" {
+			continue
+		}
+
+		if strings.Contains(network, "/") {
+			prefix, prefixError := netip.ParsePrefix(network)
+			if prefixError != nil {
+				return nil, fmt.Errorf("invalid webhook network %q: %w", network, prefixError)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		address, addressError := netip.ParseAddr(network)
+		if addressError != nil {
+			return nil, fmt.Errorf("invalid webhook network %q: %w", network, addressError)
+		}
+		address = address.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(address, address.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+func (g destinationGuard) allows(address netip.Addr) bool {
+	address = address.Unmap()
+
+	for _, allowedNetwork := range g.allowedNetworks {
+		if allowedNetwork.Contains(address) {
+			return true
+		}
+	}
+
+	if address.IsLoopback() || address.IsPrivate() || address.IsUnspecified() ||
+		address.IsLinkLocalUnicast() || address.IsLinkLocalMulticast() ||
+		address.IsInterfaceLocalMulticast() || address.IsMulticast() {
+		return false
+	}
+
+	for _, blockedNetwork := range blockedNetworks {
+		if blockedNetwork.Contains(address) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g destinationGuard) control(_ string, hostPort string, _ syscall.RawConn) error {
+	host, _, splitError := net.SplitHostPort(hostPort)
+	if splitError != nil {
+		return fmt.Errorf("%w: %s", ErrDestinationNotAllowed, hostPort)
+	}
+
+	address, parsingError := netip.ParseAddr(host)
+	if parsingError != nil || !g.allows(address) {
+		return fmt.Errorf("%w: %s", ErrDestinationNotAllowed, host)
+	}
+
+	return nil
+}
+
+// newHTTPClient builds the client used for deliveries. It never follows
+// redirects, which would otherwise let a receiver bounce requests elsewhere,
+// and it ignores proxy environment variables so the guard sees the real
+// destination.
+func newHTTPClient(timeout time.Duration, guard destinationGuard) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   guard.control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network string, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
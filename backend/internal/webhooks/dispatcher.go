@@ -0,0 +1,349 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	SignatureHeader  = "X-Webhook-Signature"
+	TimestampHeader  = "X-Webhook-Timestamp"
+	EventHeader      = "X-Webhook-Event"
+	DeliveryIDHeader = "X-Webhook-Delivery"
+
+	queueSize = 256
+
+	deliveryPruneInterval = time.Hour
+)
+
+// EventTypes lists the events a webhook can subscribe to.
+var EventTypes = []string{
+	events.TypeVoteCreated,
+	events.TypeVoteChanged,
+	events.TypeVoteWithdrawn,
+	events.TypeDeadlineReached,
+	events.TypeCalendarFinalized,
+	events.TypeStatusChanged,
+	events.TypeCalendarUpdated,
+	events.TypeTimeSlotsCreated,
+	events.TypeTimeSlotUpdated,
+	events.TypeTimeSlotDeleted,
+}
+
+type Payload struct {
+	DeliveryID string         `json:"delivery_id"`
+	Type       string         `json:"type"`
+	CalendarID string         `json:"calendar_id"`
+	OccurredAt string         `json:"occurred_at"`
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+type Options struct {
+	Workers int
+	// MaxDeliveries bounds the deliveries in flight at once, including the
+	// ones waiting for a retry. Workers wait for a free slot when it is
+	// reached, and events queue up behind them.
+	MaxDeliveries int
+	MaxAttempts   int
+	BaseBackoff   time.Duration
+	MaxBackoff    time.Duration
+	Timeout       time.Duration
+	// DeliveryRetention is how long delivery log entries are kept. Zero
+	// keeps them forever.
+	DeliveryRetention time.Duration
+	// AllowedNetworks lists addresses or CIDR ranges of internal receivers
+	// that webhooks may reach. Loopback, private and link-local addresses
+	// are refused otherwise.
+	AllowedNetworks []string
+}
+
+var DefaultOptions = Options{
+	Workers:           4,
+	MaxDeliveries:     64,
+	MaxAttempts:       6,
+	BaseBackoff:       2 * time.Second,
+	MaxBackoff:        5 * time.Minute,
+	Timeout:           10 * time.Second,
+	DeliveryRetention: 30 * 24 * time.Hour,
+}
+
+// Dispatcher delivers hub events to the webhooks registered on their
+// calendar. Failed deliveries are retried with exponential backoff and every
+// attempt is written to the delivery log. Queued events and pending retries
+// live in memory only and are lost when the process stops.
+type Dispatcher struct {
+	queries       *sqlc.Queries
+	client        *http.Client
+	options       Options
+	queue         chan events.Event
+	deliverySlots chan struct{}
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	deliveries sync.WaitGroup
+	workers    sync.WaitGroup
+}
+
+func NewDispatcher(queries *sqlc.Queries, options Options) (*Dispatcher, error) {
+	allowedNetworks, parsingError := parseNetworks(options.AllowedNetworks)
+	if parsingError != nil {
+		return nil, parsingError
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		queries:       queries,
+		client:        newHTTPClient(options.Timeout, destinationGuard{allowedNetworks: allowedNetworks}),
+		options:       options,
+		queue:         make(chan events.Event, queueSize),
+		deliverySlots: make(chan struct{}, max(options.MaxDeliveries, 1)),
+		ctx:           ctx,
+		cancel:        cancel,
+	}, nil
+}
+
+func (d *Dispatcher) Start() {
+	for range d.options.Workers {
+		d.workers.Add(1)
+		go func() {
+			defer d.workers.Done()
+			d.work()
+		}()
+	}
+
+	if d.options.DeliveryRetention > 0 {
+		d.workers.Add(1)
+		go func() {
+			defer d.workers.Done()
+			d.pruneDeliveries()
+		}()
+	}
+}
+
+// Enqueue is meant to be registered with events.Hub.AddListener. It never
+// blocks; events are dropped with a log line when the queue is full.
+func (d *Dispatcher) Enqueue(event events.Event) {
+	if !slices.Contains(EventTypes, event.Type) {
+		return
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		slog.Warn("webhook queue full, dropping event",
+			"event_type", event.Type,
+			"calendar_id", utils.UUIDToString(event.CalendarID),
+		)
+	}
+}
+
+// Close stops the workers, aborts in-flight requests and pending retries, and
+// waits until every delivery goroutine has returned.
+func (d *Dispatcher) Close() {
+	d.cancel()
+	d.workers.Wait()
+	d.deliveries.Wait()
+}
+
+func (d *Dispatcher) work() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case event := <-d.queue:
+			d.dispatch(event)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(event events.Event) {
+	webhooks, listingError := d.queries.ListWebhooksByCalendarID(d.ctx, event.CalendarID)
+	if listingError != nil {
+		slog.Error("failed to list webhooks",
+			"calendar_id", utils.UUIDToString(event.CalendarID),
+			"error", listingError,
+		)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if len(webhook.EventTypes) > 0 && !slices.Contains(webhook.EventTypes, event.Type) {
+			continue
+		}
+
+		if !d.startDelivery(webhook, event) {
+			return
+		}
+	}
+}
+
+// startDelivery waits for a free delivery slot and delivers in the
+// background. It returns false when the dispatcher is closed while waiting.
+func (d *Dispatcher) startDelivery(webhook sqlc.Webhook, event events.Event) bool {
+	select {
+	case <-d.ctx.Done():
+		return false
+	case d.deliverySlots <- struct{}{}:
+	}
+
+	d.deliveries.Add(1)
+	go func() {
+		defer d.deliveries.Done()
+		defer func() { <-d.deliverySlots }()
+		d.deliver(webhook, event)
+	}()
+	return true
+}
+
+func (d *Dispatcher) deliver(webhook sqlc.Webhook, event events.Event) {
+	deliveryID := utils.NewUUID()
+
+	body, encodingError := json.Marshal(Payload{
+		DeliveryID: utils.UUIDToString(deliveryID),
+		Type:       event.Type,
+		CalendarID: utils.UUIDToString(event.CalendarID),
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339),
+		Data:       event.Data,
+	})
+	if encodingError != nil {
+		slog.Error("failed to encode webhook payload", "error", encodingError)
+		return
+	}
+
+	for attempt := 1; attempt <= d.options.MaxAttempts; attempt++ {
+		statusCode, sendingError := d.send(webhook, event.Type, deliveryID, body)
+		succeeded := sendingError == nil && statusCode >= 200 && statusCode < 300
+
+		d.recordAttempt(webhook.ID, deliveryID, event.Type, body, attempt, statusCode, sendingError, succeeded)
+
+		if succeeded || !isRetryable(statusCode, sendingError) || attempt == d.options.MaxAttempts {
+			return
+		}
+
+		retryTimer := time.NewTimer(backoff(d.options.BaseBackoff, d.options.MaxBackoff, attempt))
+		select {
+		case <-d.ctx.Done():
+			retryTimer.Stop()
+			return
+		case <-retryTimer.C:
+		}
+	}
+}
+
+func (d *Dispatcher) send(webhook sqlc.Webhook, eventType string, deliveryID pgtype.UUID, body []byte) (int, error) {
+	request, requestError := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if requestError != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", requestError)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "meeting-planner-webhooks")
+	request.Header.Set(EventHeader, eventType)
+	request.Header.Set(DeliveryIDHeader, utils.UUIDToString(deliveryID))
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, body))
+
+	response, sendingError := d.client.Do(request)
+	if sendingError != nil {
+		return 0, sendingError
+	}
+	defer response.Body.Close()
+
+	return response.StatusCode, nil
+}
+
+func (d *Dispatcher) recordAttempt(webhookID pgtype.UUID, deliveryID pgtype.UUID, eventType string, body []byte, attempt int, statusCode int, sendingError error, succeeded bool) {
+	queryParams := sqlc.CreateWebhookDeliveryParams{
+		WebhookID:  webhookID,
+		DeliveryID: deliveryID,
+		EventType:  eventType,
+		Payload:    body,
+		Attempt:    int32(attempt),
+		Succeeded:  succeeded,
+	}
+	if statusCode != 0 {
+		recordedStatus := int32(statusCode)
+		queryParams.StatusCode = &recordedStatus
+	}
+	if sendingError != nil {
+		recordedError := sendingError.Error()
+		queryParams.Error = &recordedError
+	}
+
+	// The log is written even while shutting down, so it uses its own context.
+	recordingContext, cancelRecording := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelRecording()
+
+	if recordingError := d.queries.CreateWebhookDelivery(recordingContext, queryParams); recordingError != nil {
+		slog.Error("failed to record webhook delivery",
+			"webhook_id", utils.UUIDToString(webhookID),
+			"error", recordingError,
+		)
+	}
+}
+
+// pruneDeliveries deletes delivery log entries older than the retention
+// every deliveryPruneInterval until the dispatcher is closed.
+func (d *Dispatcher) pruneDeliveries() {
+	ticker := time.NewTicker(deliveryPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := pgtype.Timestamptz{Time: time.Now().Add(-d.options.DeliveryRetention), Valid: true}
+		if pruneError := d.queries.DeleteWebhookDeliveriesBefore(d.ctx, cutoff); pruneError != nil && d.ctx.Err() == nil {
+			slog.Error("failed to prune webhook deliveries", "error", pruneError)
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.body" keyed with secret.
+// Receivers recompute it to check the X-Webhook-Signature header.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isRetryable(statusCode int, sendingError error) bool {
+	if errors.Is(sendingError, ErrDestinationNotAllowed) {
+		return false
+	}
+	if sendingError != nil {
+		return true
+	}
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+func backoff(base time.Duration, limit time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > limit {
+		return limit
+	}
+	return delay
+}
@@ -0,0 +1,420 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"meeting-planner/backend/internal/db/sqlc"
+	"meeting-planner/backend/internal/events"
+	"meeting-planner/backend/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// deliveryLog stands in for the database and keeps the delivery rows the
+// dispatcher writes.
+type deliveryLog struct {
+	mutex sync.Mutex
+	rows  []sqlc.CreateWebhookDeliveryParams
+}
+
+func (l *deliveryLog) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.rows = append(l.rows, sqlc.CreateWebhookDeliveryParams{
+		WebhookID:  args[0].(pgtype.UUID),
+		DeliveryID: args[1].(pgtype.UUID),
+		EventType:  args[2].(string),
+		Payload:    args[3].([]byte),
+		Attempt:    args[4].(int32),
+		StatusCode: args[5].(*int32),
+		Error:      args[6].(*string),
+		Succeeded:  args[7].(bool),
+	})
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (l *deliveryLog) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (l *deliveryLog) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func (l *deliveryLog) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (l *deliveryLog) deliveries() []sqlc.CreateWebhookDeliveryParams {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return slices.Clone(l.rows)
+}
+
+func newTestDispatcher(t *testing.T, allowedNetworks []string) (*Dispatcher, *deliveryLog) {
+	t.Helper()
+
+	log := &deliveryLog{}
+	dispatcher, dispatcherError := NewDispatcher(sqlc.New(log), Options{
+		Workers:         1,
+		MaxDeliveries:   2,
+		MaxAttempts:     3,
+		BaseBackoff:     time.Millisecond,
+		MaxBackoff:      5 * time.Millisecond,
+		Timeout:         time.Second,
+		AllowedNetworks: allowedNetworks,
+	})
+	if dispatcherError != nil {
+		t.Fatalf("NewDispatcher: %v", dispatcherError)
+	}
+	t.Cleanup(dispatcher.Close)
+
+	return dispatcher, log
+}
+
+func testWebhook(url string) sqlc.Webhook {
+	return sqlc.Webhook{
+		ID:         utils.NewUUID(),
+		CalendarID: utils.NewUUID(),
+		Url:        url,
+		Secret:     "test-secret",
+	}
+}
+
+func testEvent() events.Event {
+	return events.Event{
+		Type:       events.TypeVoteCreated,
+		CalendarID: utils.NewUUID(),
+		OccurredAt: time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC),
+		Data:       map[string]any{"username": "John"},
+	}
+}
+
+type receiverStats struct {
+	mutex        sync.Mutex
+	requestTimes []time.Time
+}
+
+func (s *receiverStats) count() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.requestTimes)
+}
+
+// respondWith answers with the given status codes in turn and repeats the
+// last one once they run out.
+func respondWith(statusCodes ...int) (http.HandlerFunc, *receiverStats) {
+	stats := &receiverStats{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats.mutex.Lock()
+		requestIndex := len(stats.requestTimes)
+		stats.requestTimes = append(stats.requestTimes, time.Now())
+		stats.mutex.Unlock()
+
+		w.WriteHeader(statusCodes[min(requestIndex, len(statusCodes)-1)])
+	}, stats
+}
+
+func TestDeliverSignsTimestampAndBody(t *testing.T) {
+	var receivedHeaders http.Header
+	var receivedBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	dispatcher, log := newTestDispatcher(t, []string{"127.0.0.0/8", "::1"})
+	webhook := testWebhook(receiver.URL)
+	dispatcher.deliver(webhook, testEvent())
+
+	timestamp := receivedHeaders.Get(TimestampHeader)
+	expectedSignature := "sha256=" + Sign(webhook.Secret, timestamp, receivedBody)
+	if !hmac.Equal([]byte(receivedHeaders.Get(SignatureHeader)), []byte(expectedSignature)) {
+		t.Errorf("signature = %q, want %q", receivedHeaders.Get(SignatureHeader), expectedSignature)
+	}
+	if receivedHeaders.Get(EventHeader) != events.TypeVoteCreated {
+		t.Errorf("event header = %q, want %q", receivedHeaders.Get(EventHeader), events.TypeVoteCreated)
+	}
+
+	var payload Payload
+	if decodingError := json.Unmarshal(receivedBody, &payload); decodingError != nil {
+		t.Fatalf("payload is not JSON: %v", decodingError)
+	}
+	if payload.DeliveryID != receivedHeaders.Get(DeliveryIDHeader) {
+		t.Errorf("payload delivery_id = %q, header = %q", payload.DeliveryID, receivedHeaders.Get(DeliveryIDHeader))
+	}
+	if payload.OccurredAt != "2026-01-10T18:00:00Z" {
+		t.Errorf("payload occurred_at = %q", payload.OccurredAt)
+	}
+
+	deliveries := log.deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("recorded %d deliveries, want 1", len(deliveries))
+	}
+	if !deliveries[0].Succeeded || *deliveries[0].StatusCode != http.StatusNoContent || deliveries[0].WebhookID != webhook.ID {
+		t.Errorf("recorded delivery = %+v", deliveries[0])
+	}
+	if string(deliveries[0].Payload) != string(receivedBody) {
+		t.Errorf("recorded payload differs from the sent body")
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	testCases := []struct {
+		name              string
+		statusCodes       []int
+		expectedAttempts  int
+		expectedSucceeded bool
+	}{
+		{name: "server error then success", statusCodes: []int{500, 503, 200}, expectedAttempts: 3, expectedSucceeded: true},
+		{name: "server error every time", statusCodes: []int{502}, expectedAttempts: 3},
+		{name: "too many requests", statusCodes: []int{429, 200}, expectedAttempts: 2, expectedSucceeded: true},
+		{name: "client error", statusCodes: []int{400}, expectedAttempts: 1},
+		{name: "gone", statusCodes: []int{410}, expectedAttempts: 1},
+		{name: "redirect is not followed", statusCodes: []int{302}, expectedAttempts: 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler, stats := respondWith(testCase.statusCodes...)
+			receiver := httptest.NewServer(handler)
+			defer receiver.Close()
+
+			dispatcher, log := newTestDispatcher(t, []string{"127.0.0.0/8", "::1"})
+			dispatcher.deliver(testWebhook(receiver.URL), testEvent())
+
+			if stats.count() != testCase.expectedAttempts {
+				t.Errorf("receiver got %d requests, want %d", stats.count(), testCase.expectedAttempts)
+			}
+			for attemptIndex := 1; attemptIndex < len(stats.requestTimes); attemptIndex++ {
+				expectedDelay := backoff(dispatcher.options.BaseBackoff, dispatcher.options.MaxBackoff, attemptIndex)
+				if delay := stats.requestTimes[attemptIndex].Sub(stats.requestTimes[attemptIndex-1]); delay < expectedDelay {
+					t.Errorf("attempt %d came %v after the previous one, want at least %v", attemptIndex+1, delay, expectedDelay)
+				}
+			}
+
+			deliveries := log.deliveries()
+			if len(deliveries) != testCase.expectedAttempts {
+				t.Fatalf("recorded %d deliveries, want %d", len(deliveries), testCase.expectedAttempts)
+			}
+			for deliveryIndex, delivery := range deliveries {
+				if int(delivery.Attempt) != deliveryIndex+1 {
+					t.Errorf("delivery %d has attempt %d", deliveryIndex, delivery.Attempt)
+				}
+				if delivery.DeliveryID != deliveries[0].DeliveryID {
+					t.Errorf("attempts of one delivery have different delivery IDs")
+				}
+				expectedStatus := testCase.statusCodes[min(deliveryIndex, len(testCase.statusCodes)-1)]
+				if delivery.StatusCode == nil || int(*delivery.StatusCode) != expectedStatus {
+					t.Errorf("delivery %d status = %v, want %d", deliveryIndex, delivery.StatusCode, expectedStatus)
+				}
+			}
+			if lastDelivery := deliveries[len(deliveries)-1]; lastDelivery.Succeeded != testCase.expectedSucceeded {
+				t.Errorf("last delivery succeeded = %v, want %v", lastDelivery.Succeeded, testCase.expectedSucceeded)
+			}
+		})
+	}
+}
+
+func TestStartDeliveryBoundsDeliveriesInFlight(t *testing.T) {
+	releaseRequests := make(chan struct{})
+	handler, stats := respondWith(http.StatusOK)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r)
+		<-releaseRequests
+	}))
+	defer receiver.Close()
+
+	dispatcher, log := newTestDispatcher(t, []string{"127.0.0.0/8", "::1"})
+	webhook := testWebhook(receiver.URL)
+
+	allStarted := make(chan struct{})
+	go func() {
+		defer close(allStarted)
+		for range 3 {
+			dispatcher.startDelivery(webhook, testEvent())
+		}
+	}()
+
+	for deadline := time.Now().Add(time.Second); stats.count() < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-allStarted:
+		t.Fatal("the third delivery started while two were in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if stats.count() != 2 {
+		t.Errorf("receiver got %d requests while blocked, want 2", stats.count())
+	}
+
+	close(releaseRequests)
+	<-allStarted
+	dispatcher.deliveries.Wait()
+
+	if len(log.deliveries()) != 3 {
+		t.Errorf("recorded %d deliveries, want 3", len(log.deliveries()))
+	}
+}
+
+func TestStartDeliveryStopsWhenClosed(t *testing.T) {
+	dispatcher, _ := newTestDispatcher(t, nil)
+	for range dispatcher.options.MaxDeliveries {
+		dispatcher.deliverySlots <- struct{}{}
+	}
+	dispatcher.cancel()
+
+	if dispatcher.startDelivery(testWebhook("https://example.com"), testEvent()) {
+		t.Error("startDelivery = true after the dispatcher was closed, want false")
+	}
+	for range dispatcher.options.MaxDeliveries {
+		<-dispatcher.deliverySlots
+	}
+}
+
+// pruneLog stands in for the database and keeps the cutoffs the dispatcher
+// prunes the delivery log with.
+type pruneLog struct {
+	deliveryLog
+	cutoffs chan time.Time
+}
+
+func (l *pruneLog) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	l.cutoffs <- args[0].(pgtype.Timestamptz).Time
+	return pgconn.NewCommandTag("DELETE 0"), nil
+}
+
+func TestStartPrunesOldDeliveries(t *testing.T) {
+	log := &pruneLog{cutoffs: make(chan time.Time, 1)}
+	dispatcher, dispatcherError := NewDispatcher(sqlc.New(log), Options{
+		Workers:           1,
+		MaxDeliveries:     1,
+		DeliveryRetention: 24 * time.Hour,
+	})
+	if dispatcherError != nil {
+		t.Fatalf("NewDispatcher: %v", dispatcherError)
+	}
+
+	startTime := time.Now()
+	dispatcher.Start()
+	defer dispatcher.Close()
+
+	select {
+	case cutoff := <-log.cutoffs:
+		if expectedCutoff := startTime.Add(-24 * time.Hour); cutoff.Before(expectedCutoff) || cutoff.After(time.Now().Add(-24*time.Hour)) {
+			t.Errorf("pruned before %v, want about %v", cutoff, expectedCutoff)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the delivery log was not pruned on start")
+	}
+}
+
+func TestDeliverRetriesNetworkErrors(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiverURL := receiver.URL
+	receiver.Close()
+
+	dispatcher, log := newTestDispatcher(t, []string{"127.0.0.0/8", "::1"})
+	dispatcher.deliver(testWebhook(receiverURL), testEvent())
+
+	deliveries := log.deliveries()
+	if len(deliveries) != 3 {
+		t.Fatalf("recorded %d deliveries, want 3", len(deliveries))
+	}
+	for _, delivery := range deliveries {
+		if delivery.Succeeded || delivery.StatusCode != nil || delivery.Error == nil {
+			t.Errorf("recorded delivery = %+v, want a failed attempt with an error", delivery)
+		}
+	}
+}
+
+func TestDeliverRefusesInternalAddresses(t *testing.T) {
+	handler, stats := respondWith(http.StatusOK)
+	receiver := httptest.NewServer(handler)
+	defer receiver.Close()
+
+	dispatcher, log := newTestDispatcher(t, nil)
+	dispatcher.deliver(testWebhook(receiver.URL), testEvent())
+
+	if stats.count() != 0 {
+		t.Errorf("receiver on loopback got %d requests", stats.count())
+	}
+
+	deliveries := log.deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("recorded %d deliveries, want 1 without retries", len(deliveries))
+	}
+	if deliveries[0].Error == nil || !strings.Contains(*deliveries[0].Error, ErrDestinationNotAllowed.Error()) {
+		t.Errorf("recorded error = %v, want %q", deliveries[0].Error, ErrDestinationNotAllowed)
+	}
+}
+
+func TestDestinationGuard(t *testing.T) {
+	allowedNetworks, parsingError := parseNetworks([]string{"10.1.0.0/16", "fd00::1"})
+	if parsingError != nil {
+		t.Fatalf("parseNetworks: %v", parsingError)
+	}
+	guard := destinationGuard{allowedNetworks: allowedNetworks}
+
+	testCases := []struct {
+		address  string
+		expected bool
+	}{
+		{address: "93.184.215.14", expected: true},
+		{address: "2606:2800:21f:cb07:6820:80da:af6b:8b2c", expected: true},
+		{address: "127.0.0.1", expected: false},
+		{address: "::1", expected: false},
+		{address: "::ffff:127.0.0.1", expected: false},
+		{address: "10.0.0.1", expected: false},
+		{address: "172.16.5.4", expected: false},
+		{address: "192.168.1.1", expected: false},
+		{address: "169.254.169.254", expected: false},
+		{address: "fe80::1", expected: false},
+		{address: "fd00:ec2::254", expected: false},
+		{address: "100.100.100.200", expected: false},
+		{address: "0.0.0.0", expected: false},
+		{address: "10.1.2.3", expected: true},
+		{address: "fd00::1", expected: true},
+	}
+
+	for _, testCase := range testCases {
+		if allowed := guard.allows(netip.MustParseAddr(testCase.address)); allowed != testCase.expected {
+			t.Errorf("allows(%s) = %v, want %v", testCase.address, allowed, testCase.expected)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: 2 * time.Second},
+		{attempt: 2, expected: 4 * time.Second},
+		{attempt: 5, expected: 32 * time.Second},
+		{attempt: 9, expected: 5 * time.Minute},
+		{attempt: 80, expected: 5 * time.Minute},
+	}
+
+	for _, testCase := range testCases {
+		if delay := backoff(2*time.Second, 5*time.Minute, testCase.attempt); delay != testCase.expected {
+			t.Errorf("backoff(attempt %d) = %v, want %v", testCase.attempt, delay, testCase.expected)
+		}
+	}
+}
@@ -19,6 +19,10 @@ const router = createBrowserRouter([
       {
         path: "/calendar",
         element: <Calendar />,
+      },
+      {
+        path: "/calendars/:calendarId",
+        element: <Calendar />,
       }
     ],
   },
@@ -0,0 +1,35 @@
+export const AdminTokenNotice = ({
+  calendarId,
+  adminToken,
+}: {
+  calendarId: string;
+  adminToken: string;
+}) => {
+  const adminLink = `${window.location.origin}/calendars/${calendarId}#admin_token=${encodeURIComponent(adminToken)}`;
+
+  const handleCopy = () => {
+    void navigator.clipboard.writeText(adminLink);
+  };
+
+  return (
+    <div className="alert alert-info">
+      <p className="mb-2">
+        You are the organizer of this hangout. The admin link below lets you edit, finalize or delete it. It is saved in this browser only, so keep a copy to manage the hangout from another device. Anyone who has it can manage the hangout.
+      </p>
+      <div className="input-group">
+        <input
+          type="text"
+          className="form-control"
+          aria-label="Admin link"
+          value={adminLink}
+          readOnly
+        />
+        <button
+          className="btn btn-outline-secondary"
+          onClick={handleCopy}>
+          <i className="ri-file-copy-line" /> Copy
+        </button>
+      </div>
+    </div>
+  );
+};
@@ -1,10 +1,13 @@
 import dayjs from "dayjs";
 import { useState } from "react";
+import { useParams } from "react-router";
+import { AdminTokenNotice } from "../components/AdminTokenNotice";
 import { CalendarHeader } from "../components/CalendarHeader";
 import { DaySlotsModal } from "../components/DaySlotsModal";
 import { MonthGridView } from "../components/MonthGridView";
 import { TimeSlotConfirmationModal } from "../components/TimeSlotConfirmationModal";
 import { WeekView } from "../components/WeekView";
+import { useAdminToken } from "../hooks/useAdminToken";
 import { useMockTimeSlots } from "../hooks/useMockTimeSlots";
 import { useTimeSlotSelection } from "../hooks/useTimeSlotSelection";
 import type { TimeSlot } from "../types";
@@ -12,6 +15,8 @@ import type { TimeSlot } from "../types";
 type ViewMode = "week" | "month";
 
 export const Calendar = () => {
+  const { calendarId } = useParams();
+  const adminToken = useAdminToken(calendarId);
   const [viewMode, setViewMode] = useState<ViewMode>("week");
   const [currentWeek, setCurrentWeek] = useState(dayjs().toDate());
   const [currentMonth, setCurrentMonth] = useState(dayjs().toDate());
@@ -76,6 +81,7 @@ export const Calendar = () => {
     <div className="bg-success vh-100 overflow-auto">
       <div
         className="container py-5">
+        {calendarId && adminToken ? <AdminTokenNotice calendarId={calendarId} adminToken={adminToken} /> : null}
         <div className="card">
           <CalendarHeader
             eventName="Event name"
@@ -1,21 +1,25 @@
 import { useState } from "react";
+import { useNavigate } from "react-router";
 import { Collapse } from "../components/Collapse";
 import { Modal } from "../components/Modal";
 import { QuickSlotGenerator } from "../components/QuickSlotGenerator";
 import { TimeSlotForm } from "../components/TimeSlotForm";
 import { TimeSlotList } from "../components/TimeSlotList";
-import { useCreateCalendarMutation, useCreateCalendarTimeSlotsMutation } from "../data/calendarsApi";
+import { useCreateCalendarMutation } from "../data/calendarsApi";
 import { useHangoutForm } from "../hooks/useHangoutForm";
 import { useQuickSlotModal } from "../hooks/useQuickSlotModal";
 import { useTimeSlotModal } from "../hooks/useTimeSlotModal";
 import type { TimeSlot } from "../types";
+import { storeAdminToken } from "../utils/adminTokens";
 
 export const Home = () => {
+  const navigate = useNavigate();
   const [title, setTitle] = useState("");
   const [password, setPassword] = useState("");
   const [description, setDescription] = useState("");
   const [location, setLocation] = useState("");
   const [acceptResponsesUntil, setAcceptResponsesUntil] = useState("");
+  const [organizerEmail, setOrganizerEmail] = useState("");
 
   const {
     timeSlots,
@@ -53,7 +57,6 @@ export const Home = () => {
   };
 
   const [createCalendar, { isLoading: _isLoading }] = useCreateCalendarMutation();
-  const [createCalendarTimeSlots, { isLoading: _isCreatingTimeSlots }] = useCreateCalendarTimeSlotsMutation();
   const handleCreateCalendar = async () => {
     try {
       if (password.length > 0 && password.length < 3) {
@@ -81,21 +84,26 @@ export const Home = () => {
         return;
       }
 
-      const calendar = await createCalendar({
+      if (organizerEmail.length > 254) {
+        alert("Email cannot exceed 254 characters.");
+        return;
+      }
+
+      const createdCalendar = await createCalendar({
         title: title || "Hangout",
         description,
         location,
         accept_responses_until: acceptResponsesUntil,
-        password
-      }).unwrap();
-
-      await createCalendarTimeSlots({
-        calendar_id: calendar.id,
+        password,
+        organizer_email: organizerEmail,
         time_slots: timeSlots.map(slot => ({
           start_date: slot.startDate,
           end_date: slot.endDate,
         })),
       }).unwrap();
+
+      storeAdminToken(createdCalendar.id, createdCalendar.admin_token);
+      await navigate(`/calendars/${createdCalendar.id}`);
     } catch (err) {
       console.error("Failed to create calendar:", err);
     }
@@ -279,6 +287,19 @@ export const Home = () => {
                       onChange={(e) => { setAcceptResponsesUntil(e.target.value); }}
                     />
                   </div>
+
+                  <div className="mt-3">
+                    <label htmlFor="organizer-email">Email for the admin link and new responses</label>
+                    <input
+                      id="organizer-email"
+                      type="email"
+                      className="form-control"
+                      placeholder="Email"
+                      value={organizerEmail}
+                      maxLength={254}
+                      onChange={(e) => { setOrganizerEmail(e.target.value); }}
+                    />
+                  </div>
                 </Collapse>
               </div>
             </div>
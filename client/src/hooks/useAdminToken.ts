@@ -0,0 +1,21 @@
+import { useEffect } from "react";
+import { useLocation, useNavigate } from "react-router";
+import { getAdminToken, storeAdminToken } from "../utils/adminTokens";
+
+// Admin links carry the token in the fragment so it never reaches the server.
+// It is saved in this browser and removed from the address bar.
+export const useAdminToken = (calendarId: string | undefined) => {
+  const location = useLocation();
+  const navigate = useNavigate();
+  const fragmentToken = new URLSearchParams(location.hash.slice(1)).get("admin_token");
+
+  useEffect(() => {
+    if (!calendarId || !fragmentToken) return;
+
+    storeAdminToken(calendarId, fragmentToken);
+    void navigate({ pathname: location.pathname, search: location.search }, { replace: true });
+  }, [calendarId, fragmentToken, location.pathname, location.search, navigate]);
+
+  if (!calendarId) return null;
+  return fragmentToken ?? getAdminToken(calendarId);
+};
@@ -0,0 +1,11 @@
+const storageKey = (calendarId: string): string => {
+  return `admin_token:${calendarId}`;
+};
+
+export const storeAdminToken = (calendarId: string, adminToken: string) => {
+  localStorage.setItem(storageKey(calendarId), adminToken);
+};
+
+export const getAdminToken = (calendarId: string): string | null => {
+  return localStorage.getItem(storageKey(calendarId));
+};